@@ -0,0 +1,57 @@
+package actions
+
+import (
+	"sort"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// QuickFixes returns fixes for diagnostics produced by the
+// analysis checks. Fixes for lint rules are provided by the lint engine.
+func QuickFixes(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix {
+	switch diagnostic.Code {
+	case analysis.CodeUnresolvedTable, analysis.CodeUnresolvedColumn:
+		return referenceFixes(storage, text, diagnostic)
+	}
+	return nil
+}
+
+// Refactors returns the rewrites available for line
+func Refactors(storage *symbols.Storage, text string, line uint32) []*analysis.Fix {
+	fixes := make([]*analysis.Fix, 0)
+	for _, rel := range relationships(storage) {
+		if rel.Position.Line != line {
+			continue
+		}
+		var fix *analysis.Fix
+		if rel.Inline {
+			fix = inlineToStatement(storage, text, rel)
+		} else {
+			fix = statementToInline(storage, text, rel)
+		}
+		if fix != nil {
+			fixes = append(fixes, fix)
+		}
+	}
+
+	if fix := sortSettings(storage, text, line); fix != nil {
+		fixes = append(fixes, fix)
+	}
+	return fixes
+}
+
+// relationships returns all relationships in order of declaration
+func relationships(storage *symbols.Storage) []*symbols.Relationship {
+	rels := append([]*symbols.Relationship{}, storage.Relationships()...)
+	for _, table := range storage.Tables() {
+		rels = append(rels, table.References...)
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		if rels[i].Position.Line == rels[j].Position.Line {
+			return rels[i].Position.Offset < rels[j].Position.Offset
+		}
+		return rels[i].Position.Line < rels[j].Position.Line
+	})
+	return rels
+}
@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// inlineToStatement moves an inline ref of a column
+// into a Ref statement after the enclosing table.
+func inlineToStatement(storage *symbols.Storage, text string, rel *symbols.Relationship) *analysis.Fix {
+	table, exists := storage.ResolveTable(rel.SchemeA, rel.TableA)
+	if !exists {
+		return nil
+	}
+	target := endpoint(rel.SchemeB, rel.TableB, rel.ColumnB)
+
+	settings, _, found := analysis.ColumnSettings(text, rel.Position.Line)
+	if !found {
+		return nil
+	}
+	remaining := make([]string, 0, len(settings))
+	removed := false
+	for _, setting := range settings {
+		if !removed && strings.HasPrefix(setting, "ref") && strings.HasSuffix(setting, target) {
+			removed = true
+			continue
+		}
+		remaining = append(remaining, setting)
+	}
+	if !removed {
+		return nil
+	}
+
+	statement := fmt.Sprintf("Ref: %s %s %s", endpoint(rel.SchemeA, rel.TableA, rel.ColumnA), rel.Type, target)
+	endOfTable := tokens.UTF16Len(analysis.Line(text, table.End.Line))
+	return &analysis.Fix{
+		Title: "Convert to Ref statement",
+		Edits: []analysis.TextEdit{
+			analysis.SetColumnSettings(text, rel.Position.Line, remaining),
+			analysis.Insert(table.End.Line, endOfTable, "\n\n"+statement),
+		},
+	}
+}
+
+// statementToInline moves a single line Ref statement
+// into the settings of its host column.
+func statementToInline(storage *symbols.Storage, text string, rel *symbols.Relationship) *analysis.Fix {
+	if strings.Contains(analysis.Line(text, rel.Position.Line), "{") {
+		// long declarations are not supported
+		return nil
+	}
+	table, exists := storage.ResolveTable(rel.SchemeA, rel.TableA)
+	if !exists {
+		return nil
+	}
+	column, exists := table.ColumnByName(rel.ColumnA)
+	if !exists {
+		return nil
+	}
+
+	settings, _, _ := analysis.ColumnSettings(text, column.Position.Line)
+	settings = append(settings, fmt.Sprintf("ref: %s %s", rel.Type, endpoint(rel.SchemeB, rel.TableB, rel.ColumnB)))
+	return &analysis.Fix{
+		Title: fmt.Sprintf("Convert to inline ref on %s.%s", table.Name, column.Name),
+		Edits: []analysis.TextEdit{
+			analysis.SetColumnSettings(text, column.Position.Line, settings),
+			analysis.DeleteLine(rel.Position.Line),
+		},
+	}
+}
+
+func endpoint(scheme string, table string, column string) string {
+	return qualify(scheme, table) + "." + column
+}
+
+// qualify prefixes name with scheme if set
+func qualify(scheme string, name string) string {
+	if scheme != "" {
+		return scheme + "." + name
+	}
+	return name
+}
@@ -0,0 +1,169 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// maxSuggestions limits the "did you mean" fixes per unresolved name
+const maxSuggestions = 3
+
+// referenceFixes offers to create the missing table or column
+// and to replace it with similar existing names.
+func referenceFixes(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix {
+	fixes := make([]*analysis.Fix, 0)
+	for _, rel := range relationships(storage) {
+		if rel.Position != diagnostic.Position {
+			continue
+		}
+		sideA := side{rel.SchemeA, rel.TableA, rel.ColumnA}
+		sideB := side{rel.SchemeB, rel.TableB, rel.ColumnB}
+		fixes = append(fixes, endpointFixes(storage, text, rel, sideA, sideB)...)
+		fixes = append(fixes, endpointFixes(storage, text, rel, sideB, sideA)...)
+	}
+	return fixes
+}
+
+// side is one endpoint of a relationship
+type side struct {
+	scheme string
+	table  string
+	column string
+}
+
+// endpointFixes returns the fixes for one side of rel,
+// the other side is used to guess the type of created columns.
+func endpointFixes(storage *symbols.Storage, text string, rel *symbols.Relationship, endpoint side, other side) []*analysis.Fix {
+	tableName, columnName := endpoint.table, endpoint.column
+	columnType := "integer"
+	if table, exists := storage.ResolveTable(other.scheme, other.table); exists {
+		if column, exists := table.ColumnByName(other.column); exists {
+			columnType = column.Type
+		}
+	}
+
+	fixes := make([]*analysis.Fix, 0)
+	table, exists := storage.ResolveTable(endpoint.scheme, tableName)
+	if !exists {
+		names := make([]string, 0)
+		for _, table := range storage.Tables() {
+			names = append(names, table.Name)
+		}
+		for _, suggestion := range closest(tableName, names) {
+			if edit, found := replaceName(text, rel, tableName+".", tableName, suggestion); found {
+				fixes = append(fixes, &analysis.Fix{
+					Title: fmt.Sprintf("Did you mean %q?", suggestion),
+					Edits: []analysis.TextEdit{edit},
+				})
+			}
+		}
+
+		end := analysis.LineCount(text) - 1
+		offset := tokens.UTF16Len(analysis.Line(text, end))
+		fixes = append(fixes, &analysis.Fix{
+			Title: fmt.Sprintf("Create table %q", tableName),
+			Edits: []analysis.TextEdit{
+				analysis.Insert(end, offset, fmt.Sprintf("\n\nTable %s {\n\t%s %s\n}\n", qualify(endpoint.scheme, tableName), columnName, columnType)),
+			},
+		})
+		return fixes
+	}
+
+	if _, exists := table.ColumnByName(columnName); exists {
+		return fixes
+	}
+	names := make([]string, 0)
+	for _, column := range table.Columns {
+		names = append(names, column.Name)
+	}
+	for _, suggestion := range closest(columnName, names) {
+		if edit, found := replaceName(text, rel, tableName+"."+columnName, columnName, suggestion); found {
+			fixes = append(fixes, &analysis.Fix{
+				Title: fmt.Sprintf("Did you mean %q?", suggestion),
+				Edits: []analysis.TextEdit{edit},
+			})
+		}
+	}
+	fixes = append(fixes, &analysis.Fix{
+		Title: fmt.Sprintf("Create column %q in table %q", columnName, tableName),
+		Edits: []analysis.TextEdit{
+			analysis.Insert(table.Position.Line+1, 0, fmt.Sprintf("\t%s %s\n", columnName, columnType)),
+		},
+	})
+	return fixes
+}
+
+// replaceName replaces name inside the first occurrence of context
+// (e.g. table.column) in the lines of the relationship declaration.
+func replaceName(text string, rel *symbols.Relationship, context string, name string, replacement string) (analysis.TextEdit, bool) {
+	// long declarations span the lines after 'Ref {'
+	for line := rel.Position.Line; line <= rel.Position.Line+2; line++ {
+		position, found := analysis.FindInLine(text, line, context)
+		if !found {
+			continue
+		}
+		position.Offset += tokens.UTF16Len(context[:strings.LastIndex(context, name)])
+		position.Len = tokens.UTF16Len(name)
+		return analysis.Replace(position, replacement), true
+	}
+	return analysis.TextEdit{}, false
+}
+
+// closest returns the candidates similar to name, best match first
+func closest(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+	limit := len([]rune(name)) / 3
+	if limit < 1 {
+		limit = 1
+	}
+
+	matches := make([]match, 0)
+	for _, candidate := range candidates {
+		distance := levenshtein(name, candidate)
+		if distance > 0 && distance <= limit {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance == matches[j].distance {
+			return matches[i].name < matches[j].name
+		}
+		return matches[i].distance < matches[j].distance
+	})
+
+	names := make([]string, 0, maxSuggestions)
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a string, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
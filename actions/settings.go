@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/cst"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// settingOrder is the canonical order of column settings,
+// unknown settings are moved to the end.
+var settingOrder = []string{"pk", "primary key", "increment", "not null", "null", "unique", "default", "note", "ref"}
+
+// sortSettings orders the settings of the column declared in line.
+// Only settings that move are replaced, separators and
+// comments between them are kept as they are.
+func sortSettings(storage *symbols.Storage, text string, line uint32) *analysis.Fix {
+	table, column, found := storage.ColumnAt(line, 0)
+	if !found {
+		return nil
+	}
+	tree := cst.Parse(text)
+	columnNode, found := tree.ColumnAt(line)
+	if !found {
+		return nil
+	}
+	items := columnNode.Settings().Items()
+	if len(items) < 2 {
+		return nil
+	}
+
+	settings := make([]string, 0, len(items))
+	for _, item := range items {
+		settings = append(settings, tree.Source(item.Node))
+	}
+	sorted := slices.Clone(settings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return settingRank(sorted[i]) < settingRank(sorted[j])
+	})
+	if slices.Equal(sorted, settings) {
+		return nil
+	}
+
+	edits := make([]analysis.TextEdit, 0)
+	for i, item := range items {
+		if settings[i] != sorted[i] {
+			edits = append(edits, tree.Replace(item.Node, sorted[i]))
+		}
+	}
+	return &analysis.Fix{
+		Title: fmt.Sprintf("Sort settings of %s.%s", table.Name, column.Name),
+		Edits: edits,
+	}
+}
+
+func settingRank(setting string) int {
+	setting = strings.ToLower(setting)
+	for rank, prefix := range settingOrder {
+		if setting == prefix || strings.HasPrefix(setting, prefix+":") || strings.HasPrefix(setting, prefix+" ") {
+			return rank
+		}
+	}
+	return len(settingOrder)
+}
@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// Severity mirrors the lsp diagnostic severities
+type Severity int
+
+const (
+	SeverityError Severity = iota + 1
+	SeverityWarning
+	SeverityInformation
+	SeverityHint
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInformation:
+		return "info"
+	case SeverityHint:
+		return "hint"
+	}
+	return "unknown"
+}
+
+type Diagnostic struct {
+	Position tokens.Position
+	Severity Severity
+	// Code identifies the check that produced the diagnostic
+	Code    string
+	Message string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s %s: %s (%s)", d.Position.String(), d.Severity, d.Message, d.Code)
+}
+
+// TextEdit replaces the text between Start and End with NewText.
+// Only line and offset of the positions are used,
+// Start equal to End inserts NewText.
+type TextEdit struct {
+	Start   tokens.Position
+	End     tokens.Position
+	NewText string
+}
+
+// Fix is a quick fix for a diagnostic
+type Fix struct {
+	Title string
+	Edits []TextEdit
+}
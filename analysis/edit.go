@@ -0,0 +1,177 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+//
+// text helpers for building fixes, offsets are
+// counted in UTF-16 code units like the scanner does
+//
+
+// Line returns line number n of text without the line break
+func Line(text string, n uint32) string {
+	lines := strings.Split(text, "\n")
+	if int(n) >= len(lines) {
+		return ""
+	}
+	return strings.TrimSuffix(lines[n], "\r")
+}
+
+// LineCount returns the number of lines in text
+func LineCount(text string) uint32 {
+	return uint32(strings.Count(text, "\n") + 1)
+}
+
+// Insert returns an edit inserting newText at line:offset
+func Insert(line uint32, offset uint32, newText string) TextEdit {
+	position := tokens.Position{Line: line, Offset: offset}
+	return TextEdit{Start: position, End: position, NewText: newText}
+}
+
+// Replace returns an edit replacing the text at position
+func Replace(position tokens.Position, newText string) TextEdit {
+	end := position
+	end.Offset += position.Len
+	return TextEdit{Start: position, End: end, NewText: newText}
+}
+
+// DeleteLine returns an edit removing line including its line break
+func DeleteLine(line uint32) TextEdit {
+	return TextEdit{
+		Start: tokens.Position{Line: line},
+		End:   tokens.Position{Line: line + 1},
+	}
+}
+
+// FindInLine returns the position of the first occurrence
+// of substr in line n of text that is not part of a longer word.
+func FindInLine(text string, n uint32, substr string) (tokens.Position, bool) {
+	line := []rune(Line(text, n))
+	needle := []rune(substr)
+	for start := 0; start+len(needle) <= len(line); start++ {
+		if string(line[start:start+len(needle)]) != substr {
+			continue
+		}
+		if start > 0 && isWordRune(line[start-1]) && isWordRune(needle[0]) {
+			continue
+		}
+		end := start + len(needle)
+		if end < len(line) && isWordRune(line[end]) && isWordRune(needle[len(needle)-1]) {
+			continue
+		}
+		return tokens.Position{Line: n, Offset: column(line, start), Len: tokens.UTF16Len(substr)}, true
+	}
+	return tokens.Position{}, false
+}
+
+// ColumnSettings returns the settings of the column defined in line n,
+// e.g. [pk, not null]. position spans the brackets.
+func ColumnSettings(text string, n uint32) (settings []string, position tokens.Position, found bool) {
+	line := []rune(Line(text, n))
+	start, end := -1, -1
+	var quote rune
+	for i, char := range line {
+		if quote != 0 {
+			if char == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch char {
+		case '"', '\'', '`':
+			quote = char
+		case '[':
+			if start < 0 {
+				start = i
+			}
+		case ']':
+			if start >= 0 {
+				end = i
+			}
+		}
+	}
+	if start < 0 || end < 0 {
+		return nil, tokens.Position{Line: n, Offset: tokens.UTF16Len(strings.TrimRight(string(line), " \t"))}, false
+	}
+
+	position = tokens.Position{Line: n, Offset: column(line, start), Len: tokens.UTF16Len(string(line[start : end+1]))}
+	return splitSettings(string(line[start+1 : end])), position, true
+}
+
+// SetColumnSettings returns an edit replacing the settings of the
+// column defined in line n. Empty settings remove the brackets.
+func SetColumnSettings(text string, n uint32, settings []string) TextEdit {
+	_, position, found := ColumnSettings(text, n)
+	if !found {
+		return Insert(n, position.Offset, " ["+strings.Join(settings, ", ")+"]")
+	}
+	if len(settings) == 0 {
+		// drop the whitespace in front of the brackets as well,
+		// whitespace is one code unit per rune
+		line := []rune(Line(text, n))
+		start := runeIndex(line, position.Offset)
+		for start > 0 && (line[start-1] == ' ' || line[start-1] == '\t') {
+			start -= 1
+			position.Offset -= 1
+			position.Len += 1
+		}
+		return Replace(position, "")
+	}
+	return Replace(position, "["+strings.Join(settings, ", ")+"]")
+}
+
+// splitSettings splits at commas outside of quotes
+func splitSettings(list string) []string {
+	settings := make([]string, 0)
+	var current strings.Builder
+	var quote rune
+	for _, char := range list {
+		if quote != 0 {
+			if char == quote {
+				quote = 0
+			}
+			current.WriteRune(char)
+			continue
+		}
+		switch char {
+		case '"', '\'', '`':
+			quote = char
+			current.WriteRune(char)
+		case ',':
+			if setting := strings.TrimSpace(current.String()); setting != "" {
+				settings = append(settings, setting)
+			}
+			current.Reset()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	if setting := strings.TrimSpace(current.String()); setting != "" {
+		settings = append(settings, setting)
+	}
+	return settings
+}
+
+// column returns the UTF-16 column of rune index i in line
+func column(line []rune, i int) uint32 {
+	return tokens.UTF16Len(string(line[:i]))
+}
+
+// runeIndex returns the rune index of the UTF-16 column in line
+func runeIndex(line []rune, column uint32) int {
+	var units uint32
+	for i, char := range line {
+		if units >= column {
+			return i
+		}
+		units += tokens.UTF16RuneLen(char)
+	}
+	return len(line)
+}
+
+func isWordRune(char rune) bool {
+	return char == '_' || (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
@@ -0,0 +1,49 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+const (
+	CodeMultipleProjects    = "multiple-projects"
+	CodeUnknownDatabaseType = "unknown-database-type"
+)
+
+// CheckProject reports project definitions after the
+// first one and database types unknown to dbml.
+func CheckProject(storage *symbols.Storage) []*Diagnostic {
+	diagnostics := make([]*Diagnostic, 0)
+	projects := storage.Projects()
+	for i, project := range projects {
+		if i == 0 {
+			continue
+		}
+		diagnostics = append(diagnostics, &Diagnostic{
+			Position: project.Position,
+			Severity: SeverityError,
+			Code:     CodeMultipleProjects,
+			Message:  fmt.Sprintf("project %q is already defined, only one project is allowed", projects[0].Name),
+		})
+	}
+
+	for _, project := range projects {
+		databaseType, declared := project.Options["database_type"]
+		if !declared {
+			continue
+		}
+		if _, known := project.DatabaseType(); known {
+			continue
+		}
+		diagnostics = append(diagnostics, &Diagnostic{
+			Position: project.OptionPositions["database_type"],
+			Severity: SeverityWarning,
+			Code:     CodeUnknownDatabaseType,
+			Message: fmt.Sprintf("unknown database type %q, expected one of %s",
+				databaseType, strings.Join(symbols.DatabaseTypes, ", ")),
+		})
+	}
+	return diagnostics
+}
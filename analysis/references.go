@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+const (
+	CodeUnresolvedTable  = "unresolved-table"
+	CodeUnresolvedColumn = "unresolved-column"
+)
+
+// TableResolver looks up tables referenced as scheme.name,
+// e.g. a storage or a workspace scope spanning imports.
+type TableResolver interface {
+	ResolveTable(scheme string, name string) (*symbols.Table, bool)
+}
+
+// CheckReferences reports relationship endpoints of storage
+// pointing to tables or columns that can not be resolved in scope.
+func CheckReferences(storage *symbols.Storage, scope TableResolver) []*Diagnostic {
+	relationships := append([]*symbols.Relationship{}, storage.Relationships()...)
+	for _, table := range storage.Tables() {
+		relationships = append(relationships, table.References...)
+	}
+
+	diagnostics := make([]*Diagnostic, 0)
+	for _, rel := range relationships {
+		diagnostics = append(diagnostics, checkEndpoint(scope, rel, rel.SchemeA, rel.TableA, rel.ColumnA)...)
+		diagnostics = append(diagnostics, checkEndpoint(scope, rel, rel.SchemeB, rel.TableB, rel.ColumnB)...)
+	}
+	return diagnostics
+}
+
+func checkEndpoint(scope TableResolver, rel *symbols.Relationship, scheme string, tableName string, columnName string) []*Diagnostic {
+	table, exists := scope.ResolveTable(scheme, tableName)
+	if !exists {
+		return []*Diagnostic{{
+			Position: rel.Position,
+			Severity: SeverityError,
+			Code:     CodeUnresolvedTable,
+			Message:  fmt.Sprintf("table %q does not exist", tableName),
+		}}
+	}
+	if _, exists := table.ColumnByName(columnName); !exists {
+		return []*Diagnostic{{
+			Position: rel.Position,
+			Severity: SeverityError,
+			Code:     CodeUnresolvedColumn,
+			Message:  fmt.Sprintf("column %q does not exist in table %q", columnName, tableName),
+		}}
+	}
+	return nil
+}
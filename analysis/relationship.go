@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+const CodeIncompatibleTypes = "incompatible-ref-types"
+
+// CheckRelationshipTypes reports relationships whose endpoint
+// columns have types that are not equivalent for the database type
+// declared in the project definition.
+// Endpoints that can not be resolved are skipped.
+func CheckRelationshipTypes(storage *symbols.Storage, equivalences *TypeEquivalences) []*Diagnostic {
+	var databaseType string
+	if project := storage.GetProject(); project != nil {
+		databaseType, _ = project.DatabaseType()
+	}
+
+	diagnostics := make([]*Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		for _, rel := range table.References {
+			columnA, found := lookupColumn(storage, rel.SchemeA, rel.TableA, rel.ColumnA)
+			if !found {
+				continue
+			}
+			columnB, found := lookupColumn(storage, rel.SchemeB, rel.TableB, rel.ColumnB)
+			if !found {
+				continue
+			}
+			if equivalences.Compatible(databaseType, columnA.Type, columnB.Type) {
+				continue
+			}
+
+			diagnostics = append(diagnostics, &Diagnostic{
+				Position: rel.Position,
+				Severity: SeverityWarning,
+				Code:     CodeIncompatibleTypes,
+				Message: fmt.Sprintf("incompatible types: %s.%s is %q, %s.%s is %q",
+					rel.TableA, rel.ColumnA, columnA.Type, rel.TableB, rel.ColumnB, columnB.Type),
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+func lookupColumn(storage *symbols.Storage, scheme string, tableName string, columnName string) (*symbols.Column, bool) {
+	table, exists := storage.ResolveTable(scheme, tableName)
+	if !exists {
+		return nil, false
+	}
+	return table.ColumnByName(columnName)
+}
@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// TypeTable maps type aliases to their canonical type name.
+// Types that share a canonical name are considered compatible.
+type TypeTable map[string]string
+
+// Canonical returns the canonical name for typeName.
+// Unknown types are returned normalized but otherwise unchanged.
+func (t TypeTable) Canonical(typeName string) string {
+	normalized := NormalizeType(typeName)
+	if canonical, exists := t[normalized]; exists {
+		return canonical
+	}
+	return normalized
+}
+
+// Add registers aliases as equivalent to canonical
+func (t TypeTable) Add(canonical string, aliases ...string) {
+	canonical = NormalizeType(canonical)
+	t[canonical] = canonical
+	for _, alias := range aliases {
+		t[NormalizeType(alias)] = canonical
+	}
+}
+
+// TypeEquivalences holds one TypeTable per database type
+// (as declared by the project option 'database_type').
+type TypeEquivalences struct {
+	tables   map[string]TypeTable
+	fallback TypeTable
+}
+
+// NewTypeEquivalences returns equivalences seeded with the
+// builtin tables for the supported database types.
+func NewTypeEquivalences() *TypeEquivalences {
+	equivalences := &TypeEquivalences{
+		tables:   make(map[string]TypeTable),
+		fallback: genericTypes(),
+	}
+	equivalences.tables["postgresql"] = postgresTypes()
+	equivalences.tables["mysql"] = mysqlTypes()
+	equivalences.tables["sqlite"] = sqliteTypes()
+	return equivalences
+}
+
+// Table returns the type table for databaseType.
+// Unknown or empty database types use the generic table.
+func (e *TypeEquivalences) Table(databaseType string) TypeTable {
+	if table, exists := e.tables[normalizeDatabaseType(databaseType)]; exists {
+		return table
+	}
+	return e.fallback
+}
+
+// Add registers aliases as equivalent to canonical for databaseType,
+// creating the table for unknown database types.
+func (e *TypeEquivalences) Add(databaseType string, canonical string, aliases ...string) {
+	key := normalizeDatabaseType(databaseType)
+	table, exists := e.tables[key]
+	if !exists {
+		table = make(TypeTable)
+		e.tables[key] = table
+	}
+	table.Add(canonical, aliases...)
+}
+
+// Compatible reports whether typeA and typeB are equivalent for databaseType
+func (e *TypeEquivalences) Compatible(databaseType string, typeA string, typeB string) bool {
+	table := e.Table(databaseType)
+	return table.Canonical(typeA) == table.Canonical(typeB)
+}
+
+// NormalizeType lowercases typeName and strips
+// arguments like the length in varchar(255).
+func NormalizeType(typeName string) string {
+	typeName = strings.ToLower(strings.TrimSpace(typeName))
+	if index := strings.Index(typeName, "("); index >= 0 {
+		typeName = strings.TrimSpace(typeName[:index])
+	}
+	return strings.Join(strings.Fields(typeName), " ")
+}
+
+func normalizeDatabaseType(databaseType string) string {
+	databaseType, _ = symbols.NormalizeDatabaseType(strings.TrimSpace(databaseType))
+	return strings.ToLower(databaseType)
+}
+
+//
+// builtin tables
+//
+
+func genericTypes() TypeTable {
+	table := make(TypeTable)
+	table.Add("integer", "int", "int4")
+	table.Add("bigint", "int8")
+	table.Add("smallint", "int2")
+	table.Add("varchar", "character varying", "text", "string", "char", "character")
+	table.Add("decimal", "numeric")
+	table.Add("boolean", "bool")
+	table.Add("timestamp", "datetime")
+	return table
+}
+
+func postgresTypes() TypeTable {
+	table := make(TypeTable)
+	table.Add("integer", "int", "int4", "serial", "serial4")
+	table.Add("bigint", "int8", "bigserial", "serial8")
+	table.Add("smallint", "int2", "smallserial", "serial2")
+	table.Add("varchar", "character varying", "text", "char", "character", "bpchar")
+	table.Add("decimal", "numeric")
+	table.Add("real", "float4")
+	table.Add("double precision", "float8", "float")
+	table.Add("boolean", "bool")
+	table.Add("timestamp", "timestamp without time zone")
+	table.Add("timestamptz", "timestamp with time zone")
+	table.Add("uuid")
+	return table
+}
+
+func mysqlTypes() TypeTable {
+	table := make(TypeTable)
+	table.Add("int", "integer", "int4")
+	table.Add("bigint", "int8")
+	table.Add("smallint", "int2")
+	table.Add("tinyint", "int1", "bool", "boolean")
+	table.Add("varchar", "char", "character varying", "character")
+	table.Add("decimal", "numeric", "dec", "fixed")
+	table.Add("double", "double precision", "real")
+	return table
+}
+
+func sqliteTypes() TypeTable {
+	// sqlite only knows storage classes, columns of the
+	// same type affinity are compatible
+	table := make(TypeTable)
+	table.Add("integer", "int", "int2", "int4", "int8", "bigint", "smallint", "tinyint", "mediumint", "boolean", "bool")
+	table.Add("text", "varchar", "character varying", "char", "character", "nvarchar", "nchar", "clob", "string")
+	table.Add("real", "double", "double precision", "float")
+	table.Add("numeric", "decimal", "date", "datetime")
+	return table
+}
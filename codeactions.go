@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/h0rzn/dbml-lsp/actions"
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+func textDocumentCodeAction(context *glsp.Context, params *protocol.CodeActionParams) (any, error) {
+	document, exists := getDocument(params.TextDocument.URI)
+	if !exists {
+		return nil, nil
+	}
+	state := document.State()
+	storage := state.Snapshot.Storage
+	mapper := newLineMapper(state.Text)
+
+	codeActions := make([]protocol.CodeAction, 0)
+	for _, protocolDiagnostic := range params.Context.Diagnostics {
+		diagnostic := fromProtocolDiagnostic(mapper, protocolDiagnostic)
+
+		linter.Lock()
+		fixes := linter.engine.Fixes(storage, state.Text, diagnostic)
+		linter.Unlock()
+		fixes = append(fixes, actions.QuickFixes(storage, state.Text, diagnostic)...)
+
+		for _, fix := range fixes {
+			codeAction := toCodeAction(document.URI, mapper, fix, protocol.CodeActionKindQuickFix)
+			codeAction.Diagnostics = []protocol.Diagnostic{protocolDiagnostic}
+			codeActions = append(codeActions, codeAction)
+		}
+	}
+
+	for line := params.Range.Start.Line; line <= params.Range.End.Line; line++ {
+		for _, fix := range actions.Refactors(storage, state.Text, line) {
+			codeActions = append(codeActions, toCodeAction(document.URI, mapper, fix, protocol.CodeActionKindRefactorRewrite))
+		}
+	}
+
+	return codeActions, nil
+}
+
+func toCodeAction(uri protocol.DocumentUri, mapper *lineMapper, fix *analysis.Fix, kind protocol.CodeActionKind) protocol.CodeAction {
+	edits := make([]protocol.TextEdit, 0, len(fix.Edits))
+	for _, edit := range fix.Edits {
+		edits = append(edits, protocol.TextEdit{
+			Range: protocol.Range{
+				Start: mapper.toPosition(edit.Start.Line, edit.Start.Offset),
+				End:   mapper.toPosition(edit.End.Line, edit.End.Offset),
+			},
+			NewText: edit.NewText,
+		})
+	}
+
+	return protocol.CodeAction{
+		Title: fix.Title,
+		Kind:  &kind,
+		Edit: &protocol.WorkspaceEdit{
+			Changes: map[protocol.DocumentUri][]protocol.TextEdit{uri: edits},
+		},
+	}
+}
+
+// fromProtocolDiagnostic restores the diagnostic
+// published by toProtocolDiagnostic
+func fromProtocolDiagnostic(mapper *lineMapper, diagnostic protocol.Diagnostic) *analysis.Diagnostic {
+	restored := &analysis.Diagnostic{
+		Position: mapper.fromRange(diagnostic.Range),
+		Message:  diagnostic.Message,
+	}
+	if diagnostic.Severity != nil {
+		restored.Severity = analysis.Severity(*diagnostic.Severity)
+	}
+	if diagnostic.Code != nil {
+		if code, ok := diagnostic.Code.Value.(string); ok {
+			restored.Code = code
+		}
+	}
+	return restored
+}
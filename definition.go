@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// textDocumentDefinition resolves table and column names
+// at the cursor, following imports into other files.
+func textDocumentDefinition(context *glsp.Context, params *protocol.DefinitionParams) (any, error) {
+	document, exists := getDocument(params.TextDocument.URI)
+	if !exists {
+		return nil, nil
+	}
+
+	line := analysis.Line(document.State().Text, params.Position.Line)
+	// dottedNameAt counts runes
+	character := byteIndex(line, params.Position.Character, positionEncoding)
+	segments, index := dottedNameAt(line, utf8.RuneCountInString(line[:character]))
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	scope := document.Scope()
+	// table.column or scheme.table.column with the cursor on the column
+	if index > 0 && index == len(segments)-1 {
+		if symbol, exists := scope.Lookup(segments[index-1]); exists {
+			if column, exists := symbol.Table.ColumnByName(segments[index]); exists {
+				return location(symbol.Path, column.Position), nil
+			}
+		}
+	}
+	if symbol, exists := scope.Lookup(segments[index]); exists {
+		return location(symbol.Path, symbol.Table.Position), nil
+	}
+	return nil, nil
+}
+
+// dottedNameAt returns the dot separated name around offset,
+// e.g. [users id] for "users.id", and the index of the segment at offset.
+func dottedNameAt(line string, offset int) ([]string, int) {
+	runes := []rune(line)
+	isNameRune := func(char rune) bool {
+		return char == '.' || char == '_' || (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+	}
+	if offset > len(runes) {
+		return nil, 0
+	}
+
+	start, end := offset, offset
+	for start > 0 && isNameRune(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && isNameRune(runes[end]) {
+		end++
+	}
+	if start == end {
+		return nil, 0
+	}
+
+	segments := strings.Split(string(runes[start:end]), ".")
+	index := strings.Count(string(runes[start:offset]), ".")
+	if index >= len(segments) || segments[index] == "" {
+		return nil, 0
+	}
+	return segments, index
+}
+
+func location(path string, position tokens.Position) protocol.Location {
+	return protocol.Location{
+		URI:   pathToURI(path),
+		Range: newLineMapper(project.File(path).Text).toRange(position),
+	}
+}
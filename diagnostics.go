@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/lint"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+var (
+	diagnosticSource = "dbml-lsp"
+	linter           = struct {
+		sync.Mutex
+		engine *lint.Engine
+		// file config of the workspace and config sent by the client,
+		// the client config takes precedence
+		fileConfig   *lint.Config
+		clientConfig *lint.Config
+	}{
+		engine:     lint.NewEngine(nil),
+		fileConfig: lint.NewConfig(),
+	}
+)
+
+// collectDiagnostics runs all checks against a document state
+func collectDiagnostics(document *Document, state *DocumentState) []*analysis.Diagnostic {
+	if state.ParseErr != nil {
+		return state.Result.Diagnostics
+	}
+
+	storage := state.Snapshot.Storage
+	scope := document.Scope()
+	diagnostics := append(scope.Diagnostics, analysis.CheckReferences(storage, scope)...)
+	diagnostics = append(diagnostics, analysis.CheckProject(storage)...)
+
+	linter.Lock()
+	defer linter.Unlock()
+	return append(diagnostics, linter.engine.Run(storage)...)
+}
+
+func publishDiagnostics(context *glsp.Context, document *Document) {
+	state := document.State()
+	mapper := newLineMapper(state.Text)
+	diagnostics := make([]protocol.Diagnostic, 0)
+	for _, diagnostic := range collectDiagnostics(document, state) {
+		diagnostics = append(diagnostics, toProtocolDiagnostic(mapper, diagnostic))
+	}
+
+	version := protocol.UInteger(state.Version)
+	context.Notify(protocol.ServerTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
+		URI:         document.URI,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+// republishDiagnostics refreshes the diagnostics of all open documents
+func republishDiagnostics(context *glsp.Context) {
+	documents.Lock()
+	open := make([]*Document, 0, len(documents.items))
+	for _, document := range documents.items {
+		open = append(open, document)
+	}
+	documents.Unlock()
+
+	for _, document := range open {
+		publishDiagnostics(context, document)
+	}
+}
+
+// loadLintConfig reads the lint config file from the workspace root,
+// an invalid file is reported and the default config is used
+func loadLintConfig(rootURI string) error {
+	config, err := lint.LoadConfig(filepath.Join(uriToPath(rootURI), lint.ConfigFileName))
+	if err != nil {
+		// lint with the defaults until the config file is fixed
+		config = lint.NewConfig()
+	}
+
+	linter.Lock()
+	linter.fileConfig = config
+	linter.engine.SetConfig(config.Merge(linter.clientConfig))
+	linter.Unlock()
+	return err
+}
+
+// workspaceDidChangeConfiguration expects lint settings as
+// {"dbml": {"lint": {"rules": {...}}}}
+func workspaceDidChangeConfiguration(context *glsp.Context, params *protocol.DidChangeConfigurationParams) error {
+	data, err := json.Marshal(params.Settings)
+	if err != nil {
+		return err
+	}
+	var settings struct {
+		Dbml struct {
+			Lint json.RawMessage `json:"lint"`
+		} `json:"dbml"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+
+	clientConfig := lint.NewConfig()
+	if len(settings.Dbml.Lint) > 0 {
+		clientConfig, err = lint.ParseConfig(settings.Dbml.Lint)
+		if err != nil {
+			return err
+		}
+	}
+
+	linter.Lock()
+	linter.clientConfig = clientConfig
+	linter.engine.SetConfig(linter.fileConfig.Merge(clientConfig))
+	linter.Unlock()
+
+	republishDiagnostics(context)
+	return nil
+}
+
+func toProtocolDiagnostic(mapper *lineMapper, diagnostic *analysis.Diagnostic) protocol.Diagnostic {
+	severity := protocol.DiagnosticSeverity(diagnostic.Severity)
+	return protocol.Diagnostic{
+		Range:    mapper.toRange(diagnostic.Position),
+		Severity: &severity,
+		Code:     &protocol.IntegerOrString{Value: diagnostic.Code},
+		Source:   &diagnosticSource,
+		Message:  diagnostic.Message,
+	}
+}
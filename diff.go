@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/diff"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// runDiff implements 'dbml-lsp diff [-json] <old> <new>', files can
+// be given as git revisions like HEAD:schema.dbml
+func runDiff(args []string) error {
+	asJSON := len(args) > 0 && args[0] == "-json"
+	if asJSON {
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: dbml-lsp diff [-json] <old.dbml> <new.dbml>")
+	}
+	old, err := readStorage(args[0])
+	if err != nil {
+		return err
+	}
+	new, err := readStorage(args[1])
+	if err != nil {
+		return err
+	}
+
+	result := diff.Compare(old, new)
+	report := result.Text()
+	if asJSON {
+		if report, err = result.JSON(); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Print(report)
+	return err
+}
+
+// readStorage parses the file at path or the
+// file of a git revision like HEAD:schema.dbml
+func readStorage(path string) (*symbols.Storage, error) {
+	text, err := os.ReadFile(path)
+	if err != nil && strings.Contains(path, ":") {
+		text, err = exec.Command("git", "show", path).Output()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can not read %s: %s", path, err.Error())
+	}
+	result, err := newParser().ParseText(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return result.Symbols, nil
+}
@@ -0,0 +1,452 @@
+// Package diff compares the symbols of two versions of a schema.
+package diff
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Change is the kind of a difference
+type Change string
+
+const (
+	Added   Change = "added"
+	Removed Change = "removed"
+	Renamed Change = "renamed"
+	Altered Change = "altered"
+)
+
+// Diff lists the changes from an old to a new schema
+type Diff struct {
+	Enums         []*EnumDiff         `json:"enums,omitempty"`
+	Tables        []*TableDiff        `json:"tables,omitempty"`
+	Relationships []*RelationshipDiff `json:"relationships,omitempty"`
+}
+
+// Empty reports whether the schemas are equal
+func (d *Diff) Empty() bool {
+	return len(d.Enums) == 0 && len(d.Tables) == 0 && len(d.Relationships) == 0
+}
+
+// EnumDiff is an added, removed or altered enum
+type EnumDiff struct {
+	Change Change `json:"change"`
+	Name   string `json:"name"`
+	// Enum is the new enum, the old one for removed enums
+	Enum *symbols.Enum `json:"-"`
+	// AddedValues and RemovedValues are the changes of altered enums
+	AddedValues   []string `json:"added_values,omitempty"`
+	RemovedValues []string `json:"removed_values,omitempty"`
+}
+
+// TableDiff is an added, removed, renamed or altered table.
+// Renamed tables can have altered columns and indexes as well.
+type TableDiff struct {
+	Change Change `json:"change"`
+	// Name is the qualified name, the new name for renamed tables
+	Name    string `json:"name"`
+	OldName string `json:"old_name,omitempty"`
+	// Table is the new table, the old one for removed tables
+	Table   *symbols.Table `json:"-"`
+	Old     *symbols.Table `json:"-"`
+	Note    *Value         `json:"note,omitempty"`
+	Columns []*ColumnDiff  `json:"columns,omitempty"`
+	Indexes []*IndexDiff   `json:"indexes,omitempty"`
+}
+
+// ColumnDiff is an added, dropped or altered column
+type ColumnDiff struct {
+	Change Change `json:"change"`
+	Name   string `json:"name"`
+	// Type is the type of added and removed columns
+	Type string `json:"type,omitempty"`
+	// Column is the new column, the old one for removed columns
+	Column *symbols.Column `json:"-"`
+	Old    *symbols.Column `json:"-"`
+	// Attributes are the changes of altered columns
+	Attributes []*Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute is a changed property of a column like "type"
+type Attribute struct {
+	Name string `json:"name"`
+	Value
+}
+
+// Value is the old and the new value of a property,
+// empty if it was not set
+type Value struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// IndexDiff is an added, removed or altered index
+type IndexDiff struct {
+	Change Change `json:"change"`
+	// Name is the index name or its columns
+	Name  string         `json:"name"`
+	Index *symbols.Index `json:"-"`
+	Old   *symbols.Index `json:"-"`
+	// Attributes are the changes of altered indexes
+	Attributes []*Attribute `json:"attributes,omitempty"`
+}
+
+// RelationshipDiff is an added, removed or altered ref, altered
+// refs connect the same columns with another type or direction
+type RelationshipDiff struct {
+	Change Change `json:"change"`
+	// Ref is the normalized ref like public.orders.user_id > public.users.id
+	Ref string `json:"ref"`
+	// OldRef is the previous ref of altered refs
+	OldRef string `json:"old_ref,omitempty"`
+	// Relationship is the new ref, the old one for removed refs
+	Relationship *symbols.Relationship `json:"-"`
+	Old          *symbols.Relationship `json:"-"`
+}
+
+// columnAttributes are the compared properties of columns,
+// flags are "true" or "false"
+var columnAttributes = []struct {
+	name  string
+	value func(column *symbols.Column) string
+}{
+	{"type", func(column *symbols.Column) string { return column.Type }},
+	{"nullable", func(column *symbols.Column) string {
+		if column.Has("not null") || column.IsPrimaryKey() {
+			return "not null"
+		}
+		return "null"
+	}},
+	{"default", func(column *symbols.Column) string {
+		value, _ := column.Setting("default")
+		return value
+	}},
+	{"pk", func(column *symbols.Column) string { return flag(column.IsPrimaryKey()) }},
+	{"unique", func(column *symbols.Column) string { return flag(column.Has("unique")) }},
+	{"increment", func(column *symbols.Column) string { return flag(column.Has("increment")) }},
+	{"note", func(column *symbols.Column) string {
+		value, _ := column.Setting("note")
+		return value
+	}},
+}
+
+func flag(set bool) string {
+	if set {
+		return "true"
+	}
+	return "false"
+}
+
+// Compare returns the changes from old to new. Tables are matched by
+// their qualified name, a removed and an added table with the same
+// columns are reported as renamed.
+func Compare(old *symbols.Storage, new *symbols.Storage) *Diff {
+	diff := &Diff{Enums: compareEnums(old.Enums(), new.Enums())}
+	oldTables := make(map[string]*symbols.Table)
+	for _, table := range old.Tables() {
+		oldTables[table.QualifiedName()] = table
+	}
+	newTables := make(map[string]*symbols.Table)
+	for _, table := range new.Tables() {
+		newTables[table.QualifiedName()] = table
+	}
+
+	added := make([]*symbols.Table, 0)
+	for _, table := range new.Tables() {
+		if oldTable, exists := oldTables[table.QualifiedName()]; exists {
+			if tableDiff := compareTable(oldTable, table); tableDiff != nil {
+				diff.Tables = append(diff.Tables, tableDiff)
+			}
+			continue
+		}
+		added = append(added, table)
+	}
+	removed := make([]*symbols.Table, 0)
+	for _, table := range old.Tables() {
+		if _, exists := newTables[table.QualifiedName()]; !exists {
+			removed = append(removed, table)
+		}
+	}
+
+	// renamed maps old to new qualified names
+	renamed := make(map[string]string)
+	for _, table := range added {
+		if oldTable := takeRenamed(&removed, table); oldTable != nil {
+			tableDiff := compareTable(oldTable, table)
+			if tableDiff == nil {
+				tableDiff = &TableDiff{Name: table.QualifiedName(), Table: table, Old: oldTable}
+			}
+			tableDiff.Change = Renamed
+			tableDiff.OldName = oldTable.QualifiedName()
+			renamed[oldTable.QualifiedName()] = table.QualifiedName()
+			diff.Tables = append(diff.Tables, tableDiff)
+			continue
+		}
+		diff.Tables = append(diff.Tables, &TableDiff{Change: Added, Name: table.QualifiedName(), Table: table})
+	}
+	for _, table := range removed {
+		diff.Tables = append(diff.Tables, &TableDiff{Change: Removed, Name: table.QualifiedName(), Table: table})
+	}
+
+	diff.Relationships = compareRelationships(old, new, renamed)
+	return diff
+}
+
+func compareEnums(old []*symbols.Enum, new []*symbols.Enum) []*EnumDiff {
+	diffs := make([]*EnumDiff, 0)
+	oldEnums := make(map[string]*symbols.Enum)
+	for _, enum := range old {
+		oldEnums[enum.QualifiedName()] = enum
+	}
+	newEnums := make(map[string]*symbols.Enum)
+	for _, enum := range new {
+		newEnums[enum.QualifiedName()] = enum
+	}
+
+	for _, enum := range new {
+		oldEnum, exists := oldEnums[enum.QualifiedName()]
+		if !exists {
+			diffs = append(diffs, &EnumDiff{Change: Added, Name: enum.QualifiedName(), Enum: enum})
+			continue
+		}
+		enumDiff := &EnumDiff{
+			Change:        Altered,
+			Name:          enum.QualifiedName(),
+			Enum:          enum,
+			AddedValues:   missingValues(enum, oldEnum),
+			RemovedValues: missingValues(oldEnum, enum),
+		}
+		if len(enumDiff.AddedValues) > 0 || len(enumDiff.RemovedValues) > 0 {
+			diffs = append(diffs, enumDiff)
+		}
+	}
+	for _, enum := range old {
+		if _, exists := newEnums[enum.QualifiedName()]; !exists {
+			diffs = append(diffs, &EnumDiff{Change: Removed, Name: enum.QualifiedName(), Enum: enum})
+		}
+	}
+	return diffs
+}
+
+// missingValues returns the values of enum that are not values of other
+func missingValues(enum *symbols.Enum, other *symbols.Enum) []string {
+	values := make(map[string]bool)
+	for _, value := range other.Values {
+		values[value.Name] = true
+	}
+	missing := make([]string, 0)
+	for _, value := range enum.Values {
+		if !values[value.Name] {
+			missing = append(missing, value.Name)
+		}
+	}
+	return missing
+}
+
+// takeRenamed removes and returns the table of removed
+// with the same column names and types as table
+func takeRenamed(removed *[]*symbols.Table, table *symbols.Table) *symbols.Table {
+	for i, candidate := range *removed {
+		if columnSignature(candidate) == columnSignature(table) {
+			*removed = append((*removed)[:i], (*removed)[i+1:]...)
+			return candidate
+		}
+	}
+	return nil
+}
+
+func columnSignature(table *symbols.Table) string {
+	columns := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		columns = append(columns, column.Name+" "+column.Type)
+	}
+	return strings.Join(columns, ",")
+}
+
+// compareTable returns the changes of a table, nil if it is unchanged
+func compareTable(old *symbols.Table, new *symbols.Table) *TableDiff {
+	tableDiff := &TableDiff{Change: Altered, Name: new.QualifiedName(), Table: new, Old: old}
+	if old.Note != new.Note {
+		tableDiff.Note = &Value{Old: old.Note, New: new.Note}
+	}
+
+	for _, column := range new.Columns {
+		oldColumn, exists := old.ColumnByName(column.Name)
+		if !exists {
+			tableDiff.Columns = append(tableDiff.Columns, &ColumnDiff{Change: Added, Name: column.Name, Type: column.Type, Column: column})
+			continue
+		}
+		attributes := make([]*Attribute, 0)
+		for _, attribute := range columnAttributes {
+			oldValue, newValue := attribute.value(oldColumn), attribute.value(column)
+			if oldValue != newValue {
+				attributes = append(attributes, &Attribute{Name: attribute.name, Value: Value{Old: oldValue, New: newValue}})
+			}
+		}
+		if len(attributes) > 0 {
+			tableDiff.Columns = append(tableDiff.Columns, &ColumnDiff{
+				Change:     Altered,
+				Name:       column.Name,
+				Column:     column,
+				Old:        oldColumn,
+				Attributes: attributes,
+			})
+		}
+	}
+	for _, column := range old.Columns {
+		if _, exists := new.ColumnByName(column.Name); !exists {
+			tableDiff.Columns = append(tableDiff.Columns, &ColumnDiff{Change: Removed, Name: column.Name, Type: column.Type, Column: column})
+		}
+	}
+
+	tableDiff.Indexes = compareIndexes(old.Indexes, new.Indexes)
+	if tableDiff.Note == nil && len(tableDiff.Columns) == 0 && len(tableDiff.Indexes) == 0 {
+		return nil
+	}
+	return tableDiff
+}
+
+// IndexName returns the name of index or its columns like (a, b)
+func IndexName(index *symbols.Index) string {
+	if index.Name != "" {
+		return index.Name
+	}
+	return "(" + strings.Join(index.Columns, ", ") + ")"
+}
+
+func compareIndexes(old []*symbols.Index, new []*symbols.Index) []*IndexDiff {
+	diffs := make([]*IndexDiff, 0)
+	oldIndexes := make(map[string]*symbols.Index)
+	for _, index := range old {
+		oldIndexes[IndexName(index)] = index
+	}
+	newIndexes := make(map[string]*symbols.Index)
+	for _, index := range new {
+		newIndexes[IndexName(index)] = index
+	}
+
+	for _, index := range new {
+		name := IndexName(index)
+		oldIndex, exists := oldIndexes[name]
+		if !exists {
+			diffs = append(diffs, &IndexDiff{Change: Added, Name: name, Index: index})
+			continue
+		}
+		attributes := make([]*Attribute, 0)
+		for _, attribute := range []struct{ name, old, new string }{
+			{"columns", strings.Join(oldIndex.Columns, ", "), strings.Join(index.Columns, ", ")},
+			{"pk", flag(oldIndex.PrimaryKey), flag(index.PrimaryKey)},
+			{"unique", flag(oldIndex.Unique), flag(index.Unique)},
+			{"type", oldIndex.Type, index.Type},
+		} {
+			if attribute.old != attribute.new {
+				attributes = append(attributes, &Attribute{Name: attribute.name, Value: Value{Old: attribute.old, New: attribute.new}})
+			}
+		}
+		if len(attributes) > 0 {
+			diffs = append(diffs, &IndexDiff{Change: Altered, Name: name, Index: index, Old: oldIndex, Attributes: attributes})
+		}
+	}
+	for _, index := range old {
+		if _, exists := newIndexes[IndexName(index)]; !exists {
+			diffs = append(diffs, &IndexDiff{Change: Removed, Name: IndexName(index), Index: index})
+		}
+	}
+	return diffs
+}
+
+// ref is a relationship with resolved table names and
+// endpoints in a canonical order: '<' is flipped to '>',
+// the endpoints of '-' and '<>' are sorted
+type ref struct {
+	left, right string
+	relType     string
+	rel         *symbols.Relationship
+}
+
+// key identifies the connected columns regardless of the direction
+func (r ref) key() string {
+	if r.right < r.left {
+		return r.right + " " + r.left
+	}
+	return r.left + " " + r.right
+}
+
+func (r ref) String() string {
+	return r.left + " " + r.relType + " " + r.right
+}
+
+func refs(storage *symbols.Storage, renamed map[string]string) []ref {
+	relationships := make([]*symbols.Relationship, 0)
+	for _, table := range storage.Tables() {
+		relationships = append(relationships, table.References...)
+	}
+	relationships = append(relationships, storage.Relationships()...)
+
+	endpoint := func(scheme string, name string, column string) string {
+		qualified := symbols.QualifiedName(scheme, name)
+		if table, exists := storage.ResolveTable(scheme, name); exists {
+			qualified = table.QualifiedName()
+		}
+		if newName, exists := renamed[qualified]; exists {
+			qualified = newName
+		}
+		return qualified + "." + column
+	}
+
+	result := make([]ref, 0, len(relationships))
+	for _, rel := range relationships {
+		r := ref{
+			left:    endpoint(rel.SchemeA, rel.TableA, rel.ColumnA),
+			right:   endpoint(rel.SchemeB, rel.TableB, rel.ColumnB),
+			relType: rel.Type,
+			rel:     rel,
+		}
+		switch r.relType {
+		case "<":
+			r.left, r.right, r.relType = r.right, r.left, ">"
+		case "-", "<>":
+			if r.right < r.left {
+				r.left, r.right = r.right, r.left
+			}
+		}
+		result = append(result, r)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].key() < result[j].key() })
+	return result
+}
+
+// compareRelationships matches refs by their endpoints, refs of
+// renamed tables are compared under their new table name
+func compareRelationships(old *symbols.Storage, new *symbols.Storage, renamed map[string]string) []*RelationshipDiff {
+	diffs := make([]*RelationshipDiff, 0)
+	oldRefs := make(map[string]ref)
+	for _, r := range refs(old, renamed) {
+		oldRefs[r.key()] = r
+	}
+	newRefs := make(map[string]ref)
+	for _, r := range refs(new, nil) {
+		newRefs[r.key()] = r
+		oldRef, exists := oldRefs[r.key()]
+		switch {
+		case !exists:
+			diffs = append(diffs, &RelationshipDiff{Change: Added, Ref: r.String(), Relationship: r.rel})
+		case oldRef.String() != r.String():
+			diffs = append(diffs, &RelationshipDiff{
+				Change:       Altered,
+				Ref:          r.String(),
+				OldRef:       oldRef.String(),
+				Relationship: r.rel,
+				Old:          oldRef.rel,
+			})
+		}
+	}
+	for _, r := range refs(old, renamed) {
+		if _, exists := newRefs[r.key()]; !exists {
+			diffs = append(diffs, &RelationshipDiff{Change: Removed, Ref: r.String(), Relationship: r.rel})
+		}
+	}
+	return diffs
+}
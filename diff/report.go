@@ -0,0 +1,105 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// markers prefix the lines of the text report
+var markers = map[Change]string{
+	Added:   "+",
+	Removed: "-",
+	Renamed: "~",
+	Altered: "~",
+}
+
+// Text returns a report like
+//
+//	~ table public.orders
+//	    + column note text
+//	    ~ column total: type decimal -> numeric
+//	+ ref public.orders.user_id > public.users.id
+func (d *Diff) Text() string {
+	if d.Empty() {
+		return "no changes\n"
+	}
+	var b strings.Builder
+	for _, enum := range d.Enums {
+		fmt.Fprintf(&b, "%s enum %s\n", markers[enum.Change], enum.Name)
+		for _, value := range enum.AddedValues {
+			fmt.Fprintf(&b, "    + value %s\n", value)
+		}
+		for _, value := range enum.RemovedValues {
+			fmt.Fprintf(&b, "    - value %s\n", value)
+		}
+	}
+	for _, table := range d.Tables {
+		switch table.Change {
+		case Renamed:
+			fmt.Fprintf(&b, "~ table %s renamed to %s\n", table.OldName, table.Name)
+		default:
+			fmt.Fprintf(&b, "%s table %s\n", markers[table.Change], table.Name)
+		}
+		if table.Note != nil {
+			fmt.Fprintf(&b, "    ~ note: %s\n", table.Note)
+		}
+		for _, column := range table.Columns {
+			switch column.Change {
+			case Altered:
+				fmt.Fprintf(&b, "    ~ column %s: %s\n", column.Name, attributes(column.Attributes))
+			default:
+				fmt.Fprintf(&b, "    %s column %s %s\n", markers[column.Change], column.Name, column.Type)
+			}
+		}
+		for _, index := range table.Indexes {
+			switch index.Change {
+			case Altered:
+				fmt.Fprintf(&b, "    ~ index %s: %s\n", index.Name, attributes(index.Attributes))
+			default:
+				fmt.Fprintf(&b, "    %s index %s\n", markers[index.Change], index.Name)
+			}
+		}
+	}
+	for _, rel := range d.Relationships {
+		if rel.Change == Altered {
+			fmt.Fprintf(&b, "~ ref %s, was %s\n", rel.Ref, rel.OldRef)
+			continue
+		}
+		fmt.Fprintf(&b, "%s ref %s\n", markers[rel.Change], rel.Ref)
+	}
+	return b.String()
+}
+
+// JSON returns the indented json report
+func (d *Diff) JSON() (string, error) {
+	report, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(report) + "\n", nil
+}
+
+func (v Value) String() string {
+	return display(v.Old) + " -> " + display(v.New)
+}
+
+func display(value string) string {
+	switch value {
+	case "":
+		return "(none)"
+	case "true":
+		return "yes"
+	case "false":
+		return "no"
+	}
+	return value
+}
+
+func attributes(attributes []*Attribute) string {
+	changes := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		changes = append(changes, attribute.Name+" "+attribute.Value.String())
+	}
+	return strings.Join(changes, ", ")
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/h0rzn/dbml-lsp/parser"
+	explicitparser "github.com/h0rzn/dbml-lsp/parser/explicit_parser"
+	"github.com/h0rzn/dbml-lsp/parser/strategy"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/workspace"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// DocumentState is one version of a document. States are
+// replaced as a whole, handlers read a consistent state via State().
+type DocumentState struct {
+	Version  protocol.Integer
+	Text     string
+	Snapshot *symbols.Snapshot
+	// ParseErr is the error of the parse run for this version, if any.
+	// Snapshot holds the symbols of the last successful run then.
+	ParseErr error
+	// Result is the parse result of this version
+	Result *strategy.Result
+}
+
+type Document struct {
+	URI protocol.DocumentUri
+	// update serializes changes to the document
+	update sync.Mutex
+	state  atomic.Pointer[DocumentState]
+	parser *parser.Parser
+}
+
+func NewDocument(uri protocol.DocumentUri) *Document {
+	document := &Document{
+		URI:    uri,
+		parser: newParser(),
+	}
+	document.state.Store(&DocumentState{
+		Snapshot: document.parser.Snapshot(),
+	})
+	return document
+}
+
+// State returns the latest published state of the document
+func (d *Document) State() *DocumentState {
+	return d.state.Load()
+}
+
+// Update parses text and publishes it as the new state
+// and registers the result in the workspace.
+// apply derives the new text from the previous one, if it
+// returns an edit only the changed blocks are parsed again.
+func (d *Document) Update(version protocol.Integer, apply func(previous string) (string, *parser.Edit)) *DocumentState {
+	d.update.Lock()
+	defer d.update.Unlock()
+
+	previous := d.State()
+	text, edit := apply(previous.Text)
+	state := &DocumentState{
+		Version: version,
+		Text:    text,
+	}
+	// the snapshot is only replaced by successful runs
+	var err error
+	if edit != nil {
+		state.Result, err = d.parser.Reparse(previous.Result, text, *edit)
+	} else {
+		state.Result, err = d.parser.ParseText(text)
+	}
+	state.ParseErr = err
+	state.Snapshot = d.parser.Snapshot()
+
+	d.state.Store(state)
+	project.SetOverlay(uriToPath(d.URI), state.Text, state.Snapshot.Storage, state.ParseErr)
+	return state
+}
+
+// Scope returns the symbols visible in the document including imports
+func (d *Document) Scope() *workspace.Scope {
+	return project.Resolve(uriToPath(d.URI))
+}
+
+func newParser() *parser.Parser {
+	return parser.NewParser(explicitparser.NewParser())
+}
+
+// project holds all files of the workspace,
+// including files imported by open documents
+var project = workspace.New(func(text string) (*symbols.Storage, error) {
+	result, err := newParser().ParseText(text)
+	return result.Symbols, err
+})
+
+func uriToPath(uri protocol.DocumentUri) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return parsed.Path
+}
+
+func pathToURI(path string) protocol.DocumentUri {
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+var documents = struct {
+	sync.Mutex
+	items map[protocol.DocumentUri]*Document
+}{
+	items: make(map[protocol.DocumentUri]*Document),
+}
+
+func getDocument(uri protocol.DocumentUri) (*Document, bool) {
+	documents.Lock()
+	defer documents.Unlock()
+	document, exists := documents.items[uri]
+	return document, exists
+}
+
+func textDocumentDidOpen(context *glsp.Context, params *protocol.DidOpenTextDocumentParams) error {
+	document := NewDocument(params.TextDocument.URI)
+	document.Update(params.TextDocument.Version, func(string) (string, *parser.Edit) {
+		return params.TextDocument.Text, nil
+	})
+
+	documents.Lock()
+	documents.items[document.URI] = document
+	documents.Unlock()
+
+	publishDiagnostics(context, document)
+	return nil
+}
+
+func textDocumentDidChange(context *glsp.Context, params *protocol.DidChangeTextDocumentParams) error {
+	document, exists := getDocument(params.TextDocument.URI)
+	if !exists {
+		return nil
+	}
+
+	document.Update(params.TextDocument.Version, func(text string) (string, *parser.Edit) {
+		var edit *parser.Edit
+		for i, change := range params.ContentChanges {
+			switch change := change.(type) {
+			case protocol.TextDocumentContentChangeEvent:
+				mapper := newLineMapper(text)
+				start, end := mapper.index(change.Range.Start), mapper.index(change.Range.End)
+				text = text[:start] + change.Text + text[end:]
+				if i == 0 {
+					edit = &parser.Edit{
+						Start: change.Range.Start.Line,
+						End:   change.Range.End.Line,
+						Delta: strings.Count(change.Text, "\n") - int(change.Range.End.Line-change.Range.Start.Line),
+						Bytes: len(change.Text) - (end - start),
+					}
+				} else {
+					// several changes are parsed as a whole
+					edit = nil
+				}
+			case protocol.TextDocumentContentChangeEventWhole:
+				text = change.Text
+				edit = nil
+			}
+		}
+		return text, edit
+	})
+
+	// importing documents might be affected as well
+	republishDiagnostics(context)
+	return nil
+}
+
+func textDocumentDidClose(context *glsp.Context, params *protocol.DidCloseTextDocumentParams) error {
+	documents.Lock()
+	delete(documents.items, params.TextDocument.URI)
+	documents.Unlock()
+	project.CloseOverlay(uriToPath(params.TextDocument.URI))
+
+	// clear diagnostics of closed document
+	context.Notify(protocol.ServerTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
+		URI:         params.TextDocument.URI,
+		Diagnostics: []protocol.Diagnostic{},
+	})
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/export"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// exportCommand renders a document, its arguments are
+// the document uri and the format, e.g. "sql" or "mysql"
+const exportCommand = "dbml.export"
+
+// previewCommand renders the svg diagram of a document with tables
+// clustered by table group, its arguments are the document uri and
+// optionally the name of a table group to restrict the diagram to
+const previewCommand = "dbml.preview"
+
+// runExport implements 'dbml-lsp export [options] <format> <file>' and
+// writes the export to stdout. The options of diagram formats are
+// -schema or -group to restrict the diagram to the tables of a scheme
+// or table group, -cluster schema|group and -colors for headercolors.
+func runExport(args []string) error {
+	var options export.DiagramOptions
+	for len(args) > 2 && strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "-schema":
+			options.Scheme, args = args[1], args[2:]
+		case "-group":
+			options.Group, args = args[1], args[2:]
+		case "-cluster":
+			cluster, err := export.ParseCluster(args[1])
+			if err != nil {
+				return err
+			}
+			options.Cluster, args = cluster, args[2:]
+		case "-colors":
+			options.HeaderColors, args = true, args[1:]
+		default:
+			return fmt.Errorf("unknown option %s", args[0])
+		}
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: dbml-lsp export [-schema name] [-group name] [-cluster schema|group] [-colors] <format> <file>, formats: %v", export.Formats())
+	}
+	exporter, err := export.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	if options != (export.DiagramOptions{}) {
+		diagram, err := export.LookupDiagram(args[0])
+		if err != nil {
+			return err
+		}
+		exporter = func(storage *symbols.Storage) (string, error) {
+			return diagram(storage, options)
+		}
+	}
+	text, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	result, err := newParser().ParseText(string(text))
+	if err != nil {
+		for _, diagnostic := range result.Diagnostics {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", args[1], diagnostic)
+		}
+		return err
+	}
+	output, err := exporter(result.Symbols)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Print(output)
+	return err
+}
+
+func workspaceExecuteCommand(context *glsp.Context, params *protocol.ExecuteCommandParams) (any, error) {
+	switch params.Command {
+	case exportCommand:
+		if len(params.Arguments) != 2 {
+			return nil, errors.New("expected the document uri and the export format")
+		}
+		uri, _ := params.Arguments[0].(string)
+		format, _ := params.Arguments[1].(string)
+		exporter, err := export.Lookup(format)
+		if err != nil {
+			return nil, err
+		}
+		storage, err := exportStorage(uri)
+		if err != nil {
+			return nil, err
+		}
+		return exporter(storage)
+	case previewCommand:
+		if len(params.Arguments) < 1 || len(params.Arguments) > 2 {
+			return nil, errors.New("expected the document uri and optionally a table group")
+		}
+		uri, _ := params.Arguments[0].(string)
+		options := export.DiagramOptions{Cluster: export.ClusterGroup, HeaderColors: true}
+		if len(params.Arguments) == 2 {
+			options.Group, _ = params.Arguments[1].(string)
+		}
+		storage, err := exportStorage(uri)
+		if err != nil {
+			return nil, err
+		}
+		return export.SVG(storage, options)
+	}
+	return nil, fmt.Errorf("unknown command %q", params.Command)
+}
+
+// exportStorage returns the symbols of an open document
+// or of the file on disk
+func exportStorage(uri protocol.DocumentUri) (*symbols.Storage, error) {
+	if document, exists := getDocument(uri); exists {
+		state := document.State()
+		if state.ParseErr != nil {
+			return nil, fmt.Errorf("can not export document with errors: %s", state.ParseErr.Error())
+		}
+		return state.Snapshot.Storage, nil
+	}
+	file := project.File(uriToPath(uri))
+	return file.Storage, file.Err
+}
@@ -0,0 +1,228 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// DBML renders storage as dbml source, e.g. after
+// importing the symbols from another format
+func DBML(storage *symbols.Storage) (string, error) {
+	blocks := make([]string, 0)
+	for _, project := range storage.Projects() {
+		blocks = append(blocks, dbmlProject(project))
+	}
+	for _, enum := range storage.Enums() {
+		blocks = append(blocks, dbmlEnum(enum))
+	}
+	refs := make([]string, 0)
+	for _, table := range storage.Tables() {
+		blocks = append(blocks, dbmlTable(table))
+		for _, rel := range table.References {
+			if !rel.Inline {
+				refs = append(refs, dbmlRef(rel))
+			}
+		}
+	}
+	for _, rel := range storage.Relationships() {
+		refs = append(refs, dbmlRef(rel))
+	}
+	if len(refs) > 0 {
+		blocks = append(blocks, strings.Join(refs, "\n"))
+	}
+	for _, group := range storage.TableGroups() {
+		blocks = append(blocks, dbmlTableGroup(group))
+	}
+	for _, note := range storage.Notes() {
+		blocks = append(blocks, fmt.Sprintf("Note %s {\n  %s\n}", dbmlName(note.Name), dbmlString(note.Content)))
+	}
+	return strings.Join(blocks, "\n\n") + "\n", nil
+}
+
+func dbmlProject(project *symbols.Project) string {
+	var b strings.Builder
+	b.WriteString("Project ")
+	if project.Name != "" {
+		b.WriteString(dbmlName(project.Name) + " ")
+	}
+	b.WriteString("{\n")
+	keys := make([]string, 0, len(project.Options))
+	for key := range project.Options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  %s: %s\n", key, dbmlString(project.Options[key]))
+	}
+	if project.Note != "" {
+		fmt.Fprintf(&b, "  Note: %s\n", dbmlString(project.Note))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func dbmlEnum(enum *symbols.Enum) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Enum %s {\n", dbmlQualified(enum.Scheme, enum.Name))
+	for _, value := range enum.Values {
+		b.WriteString("  " + dbmlName(value.Name))
+		if value.Note != "" {
+			fmt.Fprintf(&b, " [note: %s]", dbmlString(value.Note))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func dbmlTableGroup(group *symbols.TableGroup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TableGroup %s ", dbmlName(group.Name))
+	if group.Color != "" {
+		fmt.Fprintf(&b, "[color: %s] ", group.Color)
+	}
+	b.WriteString("{\n")
+	for _, member := range group.Tables {
+		scheme, name, _ := strings.Cut(member, ".")
+		if scheme == symbols.DefaultScheme {
+			scheme = ""
+		}
+		b.WriteString("  " + dbmlQualified(scheme, name) + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func dbmlTable(table *symbols.Table) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Table %s ", dbmlQualified(table.Scheme, table.Name))
+	if table.Alias != "" {
+		fmt.Fprintf(&b, "as %s ", table.Alias)
+	}
+	if table.HeaderColor != "" {
+		fmt.Fprintf(&b, "[headercolor: %s] ", table.HeaderColor)
+	}
+	b.WriteString("{\n")
+	for _, column := range table.Columns {
+		fmt.Fprintf(&b, "  %s %s", dbmlName(column.Name), dbmlType(column.Type))
+		settings := make([]string, 0, len(column.Constraints))
+		for _, constraint := range column.Constraints {
+			switch constraint.Key {
+			case "":
+				settings = append(settings, constraint.Value)
+			case "default":
+				settings = append(settings, "default: "+constraint.Value)
+			default:
+				settings = append(settings, constraint.Key+": "+dbmlString(constraint.Value))
+			}
+		}
+		for _, rel := range table.References {
+			if rel.Inline && rel.ColumnA == column.Name {
+				settings = append(settings, fmt.Sprintf("ref: %s %s.%s", rel.Type,
+					dbmlQualified(rel.SchemeB, rel.TableB), dbmlName(rel.ColumnB)))
+			}
+		}
+		if len(settings) > 0 {
+			b.WriteString(" [" + strings.Join(settings, ", ") + "]")
+		}
+		b.WriteString("\n")
+	}
+	if len(table.Indexes) > 0 {
+		b.WriteString("\n  indexes {\n")
+		for _, index := range table.Indexes {
+			b.WriteString("    " + dbmlIndex(index) + "\n")
+		}
+		b.WriteString("  }\n")
+	}
+	if table.Note != "" {
+		fmt.Fprintf(&b, "\n  Note: %s\n", dbmlString(table.Note))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func dbmlIndex(index *symbols.Index) string {
+	columns := make([]string, 0, len(index.Columns))
+	for _, column := range index.Columns {
+		if isExpression(column) {
+			columns = append(columns, column)
+			continue
+		}
+		columns = append(columns, dbmlName(column))
+	}
+	line := columns[0]
+	if len(columns) > 1 {
+		line = "(" + strings.Join(columns, ", ") + ")"
+	}
+
+	settings := make([]string, 0)
+	if index.PrimaryKey {
+		settings = append(settings, "pk")
+	}
+	if index.Unique {
+		settings = append(settings, "unique")
+	}
+	if index.Name != "" {
+		settings = append(settings, "name: "+dbmlString(index.Name))
+	}
+	if index.Type != "" {
+		settings = append(settings, "type: "+index.Type)
+	}
+	if index.Note != "" {
+		settings = append(settings, "note: "+dbmlString(index.Note))
+	}
+	if len(settings) > 0 {
+		line += " [" + strings.Join(settings, ", ") + "]"
+	}
+	return line
+}
+
+func dbmlRef(rel *symbols.Relationship) string {
+	head := "Ref"
+	if rel.Name != "" {
+		head += " " + dbmlName(rel.Name)
+	}
+	return fmt.Sprintf("%s: %s.%s %s %s.%s", head,
+		dbmlQualified(rel.SchemeA, rel.TableA), dbmlName(rel.ColumnA), rel.Type,
+		dbmlQualified(rel.SchemeB, rel.TableB), dbmlName(rel.ColumnB))
+}
+
+var dbmlIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dbmlName quotes names that are keywords or no identifiers
+func dbmlName(name string) string {
+	if dbmlIdent.MatchString(name) && tokens.MapLiteral(name) == tokens.IDENT && tokens.MapKeyword(name) == tokens.IDENT {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+func dbmlQualified(scheme string, name string) string {
+	if scheme == "" {
+		return dbmlName(name)
+	}
+	return dbmlName(scheme) + "." + dbmlName(name)
+}
+
+var dbmlTypeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?(\([0-9A-Za-z_, ]*\))?$`)
+
+// dbmlType quotes types like "int unsigned" or "text[]"
+func dbmlType(columnType string) string {
+	if dbmlTypeName.MatchString(columnType) {
+		return columnType
+	}
+	return `"` + strings.ReplaceAll(columnType, `"`, `\"`) + `"`
+}
+
+// dbmlString quotes value, multi-line values use triple quotes
+func dbmlString(value string) string {
+	if strings.Contains(value, "\n") {
+		return "'''\n" + value + "\n'''"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
@@ -0,0 +1,244 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// DiagramOptions select the tables of a diagram,
+// the zero value selects all tables
+type DiagramOptions struct {
+	// Scheme restricts the diagram to the tables of a scheme
+	Scheme string
+	// Group restricts the diagram to the tables of a table group
+	Group string
+	// Cluster draws the tables of each scheme or table group in a box,
+	// formats without clusters ignore it
+	Cluster Cluster
+	// HeaderColors fills table headers with their headercolor
+	// and clusters of table groups with their color
+	HeaderColors bool
+}
+
+// Cluster is how a diagram groups tables
+type Cluster int
+
+const (
+	ClusterNone Cluster = iota
+	ClusterScheme
+	ClusterGroup
+)
+
+// ParseCluster returns the cluster of names like "schema" or "group"
+func ParseCluster(name string) (Cluster, error) {
+	switch name {
+	case "", "none":
+		return ClusterNone, nil
+	case "schema", "scheme":
+		return ClusterScheme, nil
+	case "group", "tablegroup":
+		return ClusterGroup, nil
+	}
+	return ClusterNone, fmt.Errorf("unknown cluster %q, expected schema or group", name)
+}
+
+// Diagram renders the tables of storage selected by options
+type Diagram func(storage *symbols.Storage, options DiagramOptions) (string, error)
+
+// Exporter returns the exporter rendering all tables
+func (d Diagram) Exporter() Exporter {
+	return func(storage *symbols.Storage) (string, error) {
+		return d(storage, DiagramOptions{})
+	}
+}
+
+// diagram holds the selected tables and the refs between them
+type diagram struct {
+	storage *symbols.Storage
+	options DiagramOptions
+	tables  []*symbols.Table
+	edges   []*edge
+}
+
+// cluster is a box of tables, named after the scheme or table group
+type cluster struct {
+	name   string
+	color  string
+	tables []*symbols.Table
+}
+
+// edge is a ref between two selected tables. Refs are normalized
+// so that from is the many side of '>' and the host of '-' and '<>'.
+type edge struct {
+	from       *symbols.Table
+	fromColumn string
+	to         *symbols.Table
+	toColumn   string
+	// relType is '>', '-' or '<>'
+	relType string
+}
+
+func newDiagram(storage *symbols.Storage, options DiagramOptions) (*diagram, error) {
+	d := &diagram{storage: storage, options: options}
+	var group *symbols.TableGroup
+	if options.Group != "" {
+		var exists bool
+		if group, exists = storage.TableGroupByName(options.Group); !exists {
+			return nil, fmt.Errorf("unknown table group %q", options.Group)
+		}
+	}
+	for _, table := range storage.Tables() {
+		if group != nil && !group.Contains(table) {
+			continue
+		}
+		if options.Scheme != "" && table.SchemeOrDefault() != options.Scheme {
+			continue
+		}
+		d.tables = append(d.tables, table)
+	}
+	if len(d.tables) == 0 {
+		return nil, fmt.Errorf("the diagram has no tables")
+	}
+
+	for _, rel := range allRelationships(storage) {
+		e := &edge{fromColumn: rel.ColumnA, toColumn: rel.ColumnB, relType: rel.Type}
+		var existsA, existsB bool
+		e.from, existsA = storage.ResolveTable(rel.SchemeA, rel.TableA)
+		e.to, existsB = storage.ResolveTable(rel.SchemeB, rel.TableB)
+		if !existsA || !existsB || !d.selected(e.from) || !d.selected(e.to) {
+			continue
+		}
+		if e.relType == "<" {
+			e.from, e.fromColumn, e.to, e.toColumn = e.to, e.toColumn, e.from, e.fromColumn
+			e.relType = ">"
+		}
+		d.edges = append(d.edges, e)
+	}
+	return d, nil
+}
+
+func (d *diagram) selected(table *symbols.Table) bool {
+	for _, selected := range d.tables {
+		if selected == table {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKeys returns the markers of a column
+// like "PK" or "FK", in the order PK, FK, UK
+func (d *diagram) columnKeys(table *symbols.Table, column *symbols.Column) []string {
+	keys := make([]string, 0, 3)
+	for _, name := range primaryKeyColumns(table) {
+		if name == column.Name {
+			keys = append(keys, "PK")
+			break
+		}
+	}
+	for _, e := range d.edges {
+		if e.from == table && e.fromColumn == column.Name && e.relType != "<>" {
+			keys = append(keys, "FK")
+			break
+		}
+	}
+	if column.Has("unique") || uniqueIndexed(table, column.Name) {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+// uniqueIndexed reports whether a unique index covers only column
+func uniqueIndexed(table *symbols.Table, column string) bool {
+	for _, index := range table.Indexes {
+		if index.Unique && len(index.Columns) == 1 && index.Columns[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// optional reports whether the from column of e can be null
+func (e *edge) optional() bool {
+	column, exists := e.from.ColumnByName(e.fromColumn)
+	return !exists || !(column.Has("not null") || column.IsPrimaryKey())
+}
+
+// clusters returns the clusters of the selected tables in order of
+// their first table and the tables outside of any cluster
+func (d *diagram) clusters() ([]*cluster, []*symbols.Table) {
+	clusters := make([]*cluster, 0)
+	byName := make(map[string]*cluster)
+	unclustered := make([]*symbols.Table, 0)
+	for _, table := range d.tables {
+		var c *cluster
+		switch d.options.Cluster {
+		case ClusterScheme:
+			c = &cluster{name: table.SchemeOrDefault()}
+		case ClusterGroup:
+			if group, exists := d.storage.GroupOf(table); exists {
+				c = &cluster{name: group.Name, color: group.Color}
+			}
+		}
+		if c == nil {
+			unclustered = append(unclustered, table)
+			continue
+		}
+		if existing, exists := byName[c.name]; exists {
+			c = existing
+		} else {
+			byName[c.name] = c
+			clusters = append(clusters, c)
+		}
+		c.tables = append(c.tables, table)
+	}
+	return clusters, unclustered
+}
+
+// headerColor returns the headercolor of table if
+// colors are enabled, an empty string otherwise
+func (d *diagram) headerColor(table *symbols.Table) string {
+	if !d.options.HeaderColors {
+		return ""
+	}
+	return table.HeaderColor
+}
+
+// crowsFoot returns the cardinality of e like }o--||, the referenced
+// side is exactly one, or zero or one for nullable keys
+func crowsFoot(e *edge) string {
+	to := "||"
+	if e.optional() {
+		to = "o|"
+	}
+	switch e.relType {
+	case "-":
+		return "|o--" + to
+	case "<>":
+		return "}o--o{"
+	}
+	return "}o--" + to
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// diagramID returns an identifier of table, tables outside
+// the default scheme are prefixed with it
+func diagramID(table *symbols.Table) string {
+	name := table.Name
+	if table.SchemeOrDefault() != symbols.DefaultScheme {
+		name = table.Scheme + "_" + name
+	}
+	return nonIdentifier.ReplaceAllString(name, "_")
+}
+
+// diagramLabel returns the name of table, qualified
+// for tables outside the default scheme
+func diagramLabel(table *symbols.Table) string {
+	if table.SchemeOrDefault() != symbols.DefaultScheme {
+		return table.Scheme + "." + table.Name
+	}
+	return table.Name
+}
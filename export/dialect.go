@@ -0,0 +1,331 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// EnumStyle is how a dialect declares enum columns
+type EnumStyle int
+
+const (
+	// EnumType creates a type per enum, CREATE TYPE ... AS ENUM
+	EnumType EnumStyle = iota
+	// EnumInline declares the values in the column type, ENUM('a', 'b')
+	EnumInline
+	// EnumCheck uses a text column with a CHECK constraint
+	EnumCheck
+)
+
+// IndexTypeStyle is where a dialect places the index method
+type IndexTypeStyle int
+
+const (
+	IndexTypeNone IndexTypeStyle = iota
+	// IndexTypeBefore is ON table USING hash (columns)
+	IndexTypeBefore
+	// IndexTypeAfter is ON table (columns) USING hash
+	IndexTypeAfter
+)
+
+// AlterStyle is how a dialect changes the type,
+// nullability and default of a column
+type AlterStyle int
+
+const (
+	// AlterClauses changes each property with ALTER COLUMN ... TYPE,
+	// SET NOT NULL or SET DEFAULT
+	AlterClauses AlterStyle = iota
+	// AlterModify restates the column with MODIFY COLUMN
+	AlterModify
+	// AlterDefinition restates type and nullability with ALTER COLUMN,
+	// defaults are constraints
+	AlterDefinition
+	// AlterUnsupported requires rebuilding the table
+	AlterUnsupported
+)
+
+// Dialect describes how a database spells the DDL of a schema
+type Dialect struct {
+	// Name is the database type like symbols.PostgreSQL
+	Name string
+	// Quote quotes an identifier
+	Quote func(name string) string
+	// CreateSchema returns the statement creating scheme,
+	// nil for databases without schemas. Tables are
+	// created without their scheme then.
+	CreateSchema func(scheme string) string
+	Enums        EnumStyle
+	// EnumBase is the column type of EnumCheck enums
+	EnumBase string
+	// Increment is appended to auto-increment columns
+	Increment string
+	// IncrementPrimaryKey is set if Increment declares the primary key,
+	// only a single column primary key is incremented as INTEGER then
+	IncrementPrimaryKey bool
+	// Type maps dbml column types to the database, nil keeps them
+	Type func(columnType string) string
+	// Booleans is false for databases storing booleans as 1 and 0
+	Booleans bool
+	// InlineForeignKeys declares foreign keys in CREATE TABLE
+	// for databases that can not add them with ALTER TABLE
+	InlineForeignKeys bool
+	// IndexNames generates names for unnamed indexes
+	IndexNames       bool
+	IndexType        IndexTypeStyle
+	IndexExpressions bool
+	// InlineComments declares notes as COMMENT of columns and tables
+	InlineComments bool
+	// TableComment and ColumnComment return the statements
+	// adding notes, nil if notes are dropped
+	TableComment  func(d *Dialect, scheme string, table string, note string) string
+	ColumnComment func(d *Dialect, scheme string, table string, column string, note string) string
+
+	// migrations
+
+	// AddColumn is the ALTER TABLE action adding a column
+	AddColumn   string
+	AlterColumn AlterStyle
+	// DropForeignKey is the ALTER TABLE action dropping a
+	// foreign key, empty if foreign keys can not be dropped
+	DropForeignKey string
+	// ForeignKeyName is the name the database generates for
+	// unnamed foreign keys, nil if it can not be derived
+	ForeignKeyName func(table string, column string) string
+	// RenameTable returns the statement renaming or moving a table
+	RenameTable func(d *Dialect, from tableName, to tableName) string
+	// DropIndex returns the statement dropping the index name of table
+	DropIndex func(d *Dialect, table tableName, name string) string
+}
+
+// tableName is the unquoted scheme and name of a table
+type tableName struct {
+	scheme string
+	name   string
+}
+
+// Dialects returns the builtin dialects
+func Dialects() []*Dialect {
+	return []*Dialect{PostgresDialect, MySQLDialect, SQLiteDialect, SQLServerDialect}
+}
+
+// DialectOf returns the dialect of a database type like symbols.MySQL
+func DialectOf(databaseType string) (*Dialect, bool) {
+	for _, dialect := range Dialects() {
+		if dialect.Name == databaseType {
+			return dialect, true
+		}
+	}
+	return nil, false
+}
+
+var PostgresDialect = &Dialect{
+	Name:  symbols.PostgreSQL,
+	Quote: doubleQuote,
+	CreateSchema: func(scheme string) string {
+		return "CREATE SCHEMA IF NOT EXISTS " + doubleQuote(scheme) + ";"
+	},
+	Enums:     EnumType,
+	Increment: "GENERATED BY DEFAULT AS IDENTITY",
+	Booleans:  true,
+	IndexType: IndexTypeBefore,
+	// expressions like lower(name)
+	IndexExpressions: true,
+	TableComment: func(d *Dialect, scheme string, table string, note string) string {
+		return fmt.Sprintf("COMMENT ON TABLE %s IS %s;", d.qualified(scheme, table), quoteString(note))
+	},
+	ColumnComment: func(d *Dialect, scheme string, table string, column string, note string) string {
+		return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", d.qualified(scheme, table), d.Quote(column), quoteString(note))
+	},
+	AddColumn:      "ADD COLUMN",
+	AlterColumn:    AlterClauses,
+	DropForeignKey: "DROP CONSTRAINT",
+	ForeignKeyName: func(table string, column string) string {
+		return table + "_" + column + "_fkey"
+	},
+	RenameTable: func(d *Dialect, from tableName, to tableName) string {
+		statements := make([]string, 0, 2)
+		if from.scheme != to.scheme {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s;",
+				d.qualified(from.scheme, from.name), d.Quote(sqlScheme(to.scheme, symbols.DefaultScheme))))
+		}
+		if from.name != to.name {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+				d.qualified(to.scheme, from.name), d.Quote(to.name)))
+		}
+		return strings.Join(statements, "\n")
+	},
+	DropIndex: func(d *Dialect, table tableName, name string) string {
+		return "DROP INDEX " + d.qualified(table.scheme, name) + ";"
+	},
+}
+
+var MySQLDialect = &Dialect{
+	Name: symbols.MySQL,
+	Quote: func(name string) string {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	},
+	CreateSchema: func(scheme string) string {
+		return "CREATE SCHEMA IF NOT EXISTS `" + strings.ReplaceAll(scheme, "`", "``") + "`;"
+	},
+	Enums:     EnumInline,
+	Increment: "AUTO_INCREMENT",
+	Type: typeMapper(map[string]string{
+		"varchar":           "VARCHAR(255)",
+		"character varying": "VARCHAR(255)",
+		"string":            "VARCHAR(255)",
+		"uuid":              "CHAR(36)",
+		"jsonb":             "JSON",
+	}),
+	Booleans:   true,
+	IndexNames: true,
+	IndexType:  IndexTypeAfter,
+	// functional key parts since MySQL 8.0.13
+	IndexExpressions: true,
+	InlineComments:   true,
+	AddColumn:        "ADD COLUMN",
+	AlterColumn:      AlterModify,
+	DropForeignKey:   "DROP FOREIGN KEY",
+	RenameTable: func(d *Dialect, from tableName, to tableName) string {
+		return fmt.Sprintf("RENAME TABLE %s TO %s;", d.qualified(from.scheme, from.name), d.qualified(to.scheme, to.name))
+	},
+	DropIndex: func(d *Dialect, table tableName, name string) string {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", d.Quote(name), d.qualified(table.scheme, table.name))
+	},
+}
+
+var SQLiteDialect = &Dialect{
+	Name:                symbols.SQLite,
+	Quote:               doubleQuote,
+	Enums:               EnumCheck,
+	EnumBase:            "TEXT",
+	Increment:           "PRIMARY KEY AUTOINCREMENT",
+	IncrementPrimaryKey: true,
+	Type: typeMapper(map[string]string{
+		"int":     "INTEGER",
+		"integer": "INTEGER",
+	}),
+	InlineForeignKeys: true,
+	IndexNames:        true,
+	IndexExpressions:  true,
+	AddColumn:         "ADD COLUMN",
+	AlterColumn:       AlterUnsupported,
+	RenameTable: func(d *Dialect, from tableName, to tableName) string {
+		return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", d.Quote(from.name), d.Quote(to.name))
+	},
+	DropIndex: func(d *Dialect, table tableName, name string) string {
+		return "DROP INDEX " + d.Quote(name) + ";"
+	},
+}
+
+var SQLServerDialect = &Dialect{
+	Name: symbols.SQLServer,
+	Quote: func(name string) string {
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	},
+	CreateSchema: func(scheme string) string {
+		return fmt.Sprintf("IF SCHEMA_ID(%s) IS NULL EXEC('CREATE SCHEMA [%s]');",
+			quoteString(scheme), strings.ReplaceAll(strings.ReplaceAll(scheme, "]", "]]"), "'", "''"))
+	},
+	Enums:     EnumCheck,
+	EnumBase:  "NVARCHAR(255)",
+	Increment: "IDENTITY(1,1)",
+	Type: typeMapper(map[string]string{
+		"varchar":           "NVARCHAR(255)",
+		"character varying": "NVARCHAR(255)",
+		"string":            "NVARCHAR(255)",
+		"char":              "NCHAR",
+		"text":              "NVARCHAR(MAX)",
+		"json":              "NVARCHAR(MAX)",
+		"jsonb":             "NVARCHAR(MAX)",
+		"bool":              "BIT",
+		"boolean":           "BIT",
+		"timestamp":         "DATETIME2",
+		"timestamptz":       "DATETIMEOFFSET",
+		"uuid":              "UNIQUEIDENTIFIER",
+	}),
+	IndexNames: true,
+	TableComment: func(d *Dialect, scheme string, table string, note string) string {
+		return fmt.Sprintf("EXEC sp_addextendedproperty 'MS_Description', %s, 'SCHEMA', %s, 'TABLE', %s;",
+			quoteString(note), quoteString(sqlServerScheme(scheme)), quoteString(table))
+	},
+	ColumnComment: func(d *Dialect, scheme string, table string, column string, note string) string {
+		return fmt.Sprintf("EXEC sp_addextendedproperty 'MS_Description', %s, 'SCHEMA', %s, 'TABLE', %s, 'COLUMN', %s;",
+			quoteString(note), quoteString(sqlServerScheme(scheme)), quoteString(table), quoteString(column))
+	},
+	AddColumn:      "ADD",
+	AlterColumn:    AlterDefinition,
+	DropForeignKey: "DROP CONSTRAINT",
+	RenameTable: func(d *Dialect, from tableName, to tableName) string {
+		statements := make([]string, 0, 2)
+		if from.scheme != to.scheme {
+			statements = append(statements, fmt.Sprintf("ALTER SCHEMA %s TRANSFER %s;",
+				d.Quote(sqlServerScheme(to.scheme)), d.qualified(from.scheme, from.name)))
+		}
+		if from.name != to.name {
+			statements = append(statements, fmt.Sprintf("EXEC sp_rename %s, %s;",
+				quoteString(sqlServerScheme(to.scheme)+"."+from.name), quoteString(to.name)))
+		}
+		return strings.Join(statements, "\n")
+	},
+	DropIndex: func(d *Dialect, table tableName, name string) string {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", d.Quote(name), d.qualified(table.scheme, table.name))
+	},
+}
+
+// qualified returns the quoted scheme.name, without
+// scheme for dialects that do not support schemas
+func (d *Dialect) qualified(scheme string, name string) string {
+	if scheme == "" || d.CreateSchema == nil {
+		return d.Quote(name)
+	}
+	return d.Quote(scheme) + "." + d.Quote(name)
+}
+
+// columnType maps columnType with the Type of the dialect
+func (d *Dialect) columnType(columnType string) string {
+	if d.Type == nil {
+		return columnType
+	}
+	return d.Type(columnType)
+}
+
+// typeMapper replaces the base type of a column type. Arguments like
+// the length in varchar(100) replace the arguments of the mapped type,
+// which are the defaults for types declared without arguments.
+func typeMapper(types map[string]string) func(string) string {
+	return func(columnType string) string {
+		base, args, _ := strings.Cut(columnType, "(")
+		mapped, exists := types[strings.ToLower(strings.TrimSpace(base))]
+		if !exists {
+			return columnType
+		}
+		if args == "" {
+			return mapped
+		}
+		mappedBase, _, _ := strings.Cut(mapped, "(")
+		return mappedBase + "(" + args
+	}
+}
+
+func sqlServerScheme(scheme string) string {
+	return sqlScheme(scheme, "dbo")
+}
+
+// sqlScheme returns scheme or the default scheme of the database
+func sqlScheme(scheme string, defaultScheme string) string {
+	if scheme == "" {
+		return defaultScheme
+	}
+	return scheme
+}
+
+func doubleQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
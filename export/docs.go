@@ -0,0 +1,178 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// dictionary is the data dictionary of a document, the content
+// shared by the Markdown and the HTML documentation
+type dictionary struct {
+	storage *symbols.Storage
+	title   string
+	// intro is the project note
+	intro   string
+	schemes []*schemeDocs
+	notes   []*symbols.Note
+}
+
+type schemeDocs struct {
+	name   string
+	tables []*tableDocs
+	enums  []*symbols.Enum
+}
+
+type tableDocs struct {
+	table *symbols.Table
+	// outgoing are the refs of columns of the table,
+	// incoming the refs to columns of the table
+	outgoing []*reference
+	incoming []*reference
+}
+
+// reference is a ref seen from one of its tables: column of
+// that table relates by relType to target of the other table
+type reference struct {
+	column  string
+	relType string
+	// table is nil if the other table is not declared
+	table       *symbols.Table
+	tableName   string
+	target      string
+	description string
+}
+
+func newDictionary(storage *symbols.Storage) *dictionary {
+	d := &dictionary{storage: storage, title: "Data dictionary", notes: storage.Notes()}
+	if project := storage.GetProject(); project != nil {
+		if project.Name != "" {
+			d.title = project.Name
+		}
+		d.intro = project.Note
+	}
+
+	byName := make(map[string]*schemeDocs)
+	scheme := func(name string) *schemeDocs {
+		if s, exists := byName[name]; exists {
+			return s
+		}
+		s := &schemeDocs{name: name}
+		byName[name] = s
+		d.schemes = append(d.schemes, s)
+		return s
+	}
+	tables := make(map[*symbols.Table]*tableDocs)
+	for _, table := range storage.Tables() {
+		docs := &tableDocs{table: table}
+		tables[table] = docs
+		s := scheme(table.SchemeOrDefault())
+		s.tables = append(s.tables, docs)
+	}
+	for _, enum := range storage.Enums() {
+		s := scheme(enum.SchemeOrDefault())
+		s.enums = append(s.enums, enum)
+	}
+
+	for _, rel := range allRelationships(storage) {
+		tableA, existsA := storage.ResolveTable(rel.SchemeA, rel.TableA)
+		tableB, existsB := storage.ResolveTable(rel.SchemeB, rel.TableB)
+		if existsA {
+			tables[tableA].outgoing = append(tables[tableA].outgoing, &reference{
+				column:      rel.ColumnA,
+				relType:     rel.Type,
+				table:       tableB,
+				tableName:   referencedName(tableB, rel.SchemeB, rel.TableB),
+				target:      rel.ColumnB,
+				description: relationDescription(rel.Type),
+			})
+		}
+		if existsB && tableB != tableA {
+			tables[tableB].incoming = append(tables[tableB].incoming, &reference{
+				column:      rel.ColumnB,
+				relType:     reverseRelation(rel.Type),
+				table:       tableA,
+				tableName:   referencedName(tableA, rel.SchemeA, rel.TableA),
+				target:      rel.ColumnA,
+				description: relationDescription(reverseRelation(rel.Type)),
+			})
+		}
+	}
+	return d
+}
+
+// referencedName returns the qualified name of the declared
+// table or the name the ref uses for undeclared tables
+func referencedName(table *symbols.Table, scheme string, name string) string {
+	if table != nil {
+		return table.QualifiedName()
+	}
+	return symbols.QualifiedName(scheme, name)
+}
+
+// reverseRelation returns the type of a ref read from its other side
+func reverseRelation(relType string) string {
+	switch relType {
+	case ">":
+		return "<"
+	case "<":
+		return ">"
+	}
+	return relType
+}
+
+func relationDescription(relType string) string {
+	switch relType {
+	case ">":
+		return "many to one"
+	case "<":
+		return "one to many"
+	case "-":
+		return "one to one"
+	case "<>":
+		return "many to many"
+	}
+	return relType
+}
+
+// tableAnchor and enumAnchor return the ids of the sections
+func tableAnchor(table *symbols.Table) string {
+	return "table-" + anchorName(table.QualifiedName())
+}
+
+func enumAnchor(enum *symbols.Enum) string {
+	return "enum-" + anchorName(enum.QualifiedName())
+}
+
+func anchorName(name string) string {
+	return strings.ToLower(nonWord.ReplaceAllString(name, "-"))
+}
+
+// columnSettings returns the flags of column like pk or not null
+func columnSettings(column *symbols.Column) []string {
+	settings := make([]string, 0)
+	for _, constraint := range column.Constraints {
+		if constraint.Key == "" {
+			settings = append(settings, constraint.Value)
+		}
+	}
+	return settings
+}
+
+// indexDescription returns the columns and settings of index
+func indexDescription(index *symbols.Index) (string, []string) {
+	settings := make([]string, 0)
+	switch {
+	case index.PrimaryKey:
+		settings = append(settings, "pk")
+	case index.Unique:
+		settings = append(settings, "unique")
+	}
+	if index.Type != "" {
+		settings = append(settings, "type: "+index.Type)
+	}
+	if index.Name != "" {
+		settings = append(settings, "name: "+index.Name)
+	}
+	return strings.Join(index.Columns, ", "), settings
+}
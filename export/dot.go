@@ -0,0 +1,118 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// defaultHeaderColor fills the headers of tables without headercolor
+const defaultHeaderColor = "#E8E8E8"
+
+// DOT renders the selected tables as Graphviz digraph. Tables are
+// HTML-like labels with a port per column, so that edges connect
+// the referencing and the referenced column.
+func DOT(storage *symbols.Storage, options DiagramOptions) (string, error) {
+	d, err := newDiagram(storage, options)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("digraph dbml {\n")
+	b.WriteString("  graph [rankdir=LR, fontname=\"Helvetica\"];\n")
+	b.WriteString("  node [shape=plain, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [dir=both, fontname=\"Helvetica\"];\n")
+
+	clusters, unclustered := d.clusters()
+	for _, c := range clusters {
+		fmt.Fprintf(&b, "\n  subgraph %s {\n", dotID("cluster_"+c.name))
+		fmt.Fprintf(&b, "    label=%s;\n", dotID(c.name))
+		if color := c.color; color != "" && options.HeaderColors {
+			fmt.Fprintf(&b, "    color=%s;\n", dotID(color))
+		}
+		for _, table := range c.tables {
+			d.dotTable(&b, "    ", table)
+		}
+		b.WriteString("  }\n")
+	}
+	if len(unclustered) > 0 {
+		b.WriteString("\n")
+	}
+	for _, table := range unclustered {
+		d.dotTable(&b, "  ", table)
+	}
+
+	if len(d.edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, e := range d.edges {
+		tail, head := dotArrows(e)
+		fmt.Fprintf(&b, "  %s:%s:e -> %s:%s:w [arrowtail=%s, arrowhead=%s];\n",
+			dotID(diagramLabel(e.from)), dotID(e.fromColumn),
+			dotID(diagramLabel(e.to)), dotID(e.toColumn), tail, head)
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+func (d *diagram) dotTable(b *strings.Builder, indent string, table *symbols.Table) {
+	header, text := defaultHeaderColor, "#000000"
+	if color := d.headerColor(table); color != "" {
+		header, text = color, contrastColor(color)
+	}
+	fmt.Fprintf(b, "%s%s [label=<\n", indent, dotID(diagramLabel(table)))
+	fmt.Fprintf(b, "%s  <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n", indent)
+	fmt.Fprintf(b, "%s    <tr><td colspan=\"2\" bgcolor=\"%s\"><font color=\"%s\"><b>%s</b></font></td></tr>\n",
+		indent, header, text, html.EscapeString(diagramLabel(table)))
+	for _, column := range table.Columns {
+		name := html.EscapeString(column.Name)
+		if keys := d.columnKeys(table, column); len(keys) > 0 {
+			name += " <i>" + strings.Join(keys, ", ") + "</i>"
+		}
+		fmt.Fprintf(b, "%s    <tr><td port=\"%s\" align=\"left\">%s</td><td align=\"left\">%s</td></tr>\n",
+			indent, html.EscapeString(column.Name), name, html.EscapeString(column.Type))
+	}
+	fmt.Fprintf(b, "%s  </table>\n%s>];\n", indent, indent)
+}
+
+// dotArrows returns the arrow shapes of the tail and the head of e in
+// crow's foot notation, the shape next to the table is listed first
+func dotArrows(e *edge) (string, string) {
+	head := "teetee"
+	if e.optional() {
+		head = "teeodot"
+	}
+	switch e.relType {
+	case "-":
+		return "teeodot", head
+	case "<>":
+		return "crowodot", "crowodot"
+	}
+	return "crowodot", head
+}
+
+// dotID quotes an identifier
+func dotID(id string) string {
+	return strconv.Quote(id)
+}
+
+// contrastColor returns black or white, whichever is
+// readable on background, a color like #3498DB or #abc
+func contrastColor(background string) string {
+	hex := strings.TrimPrefix(background, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return "#000000"
+	}
+	r, g, b := float64(value>>16&0xff), float64(value>>8&0xff), float64(value&0xff)
+	if 0.299*r+0.587*g+0.114*b > 150 {
+		return "#000000"
+	}
+	return "#FFFFFF"
+}
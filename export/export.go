@@ -0,0 +1,72 @@
+// Package export renders the symbols of a document in other formats,
+// e.g. as the SQL DDL creating the schema.
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Exporter renders storage in one format
+type Exporter func(storage *symbols.Storage) (string, error)
+
+// exporters by format name, "sql" selects the
+// dialect by the database type of the project
+var exporters = map[string]Exporter{
+	"dbml":      DBML,
+	"sql":       SQL,
+	"postgres":  PostgresDialect.Exporter(),
+	"mysql":     MySQLDialect.Exporter(),
+	"sqlite":    SQLiteDialect.Exporter(),
+	"sqlserver": SQLServerDialect.Exporter(),
+	"mermaid":   Diagram(Mermaid).Exporter(),
+	"dot":       Diagram(DOT).Exporter(),
+	"plantuml":  Diagram(PlantUML).Exporter(),
+	"svg":       Diagram(SVG).Exporter(),
+	"markdown":  Markdown,
+	"html":      HTML,
+}
+
+// diagrams by format name, they are exporters of all tables as well
+var diagrams = map[string]Diagram{
+	"mermaid":  Mermaid,
+	"dot":      DOT,
+	"plantuml": PlantUML,
+	"svg":      SVG,
+}
+
+// Lookup returns the exporter of format
+func Lookup(format string) (Exporter, error) {
+	exporter, exists := exporters[strings.ToLower(format)]
+	if !exists {
+		return nil, fmt.Errorf("unknown export format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+	}
+	return exporter, nil
+}
+
+// LookupDiagram returns the diagram of format
+func LookupDiagram(format string) (Diagram, error) {
+	diagram, exists := diagrams[strings.ToLower(format)]
+	if !exists {
+		names := make([]string, 0, len(diagrams))
+		for name := range diagrams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%q is no diagram format, expected one of %s", format, strings.Join(names, ", "))
+	}
+	return diagram, nil
+}
+
+// Formats returns the names of all export formats
+func Formats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,79 @@
+package export_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/h0rzn/dbml-lsp/export"
+	"github.com/h0rzn/dbml-lsp/parser"
+	explicitparser "github.com/h0rzn/dbml-lsp/parser/explicit_parser"
+)
+
+var update = flag.Bool("update", false, "write the golden files instead of comparing with them")
+
+// formats and the extensions of their golden files
+var formats = []struct{ name, extension string }{
+	{"postgres", "sql"},
+	{"mysql", "sql"},
+	{"sqlite", "sql"},
+	{"sqlserver", "sql"},
+	{"mermaid", "mmd"},
+	{"dot", "dot"},
+	{"plantuml", "puml"},
+	{"svg", "svg"},
+	{"markdown", "md"},
+	{"html", "html"},
+}
+
+// TestGolden compares the export of every format for the .dbml fixtures
+// in testdata with testdata/<fixture>.<format>.<extension>. Run with
+// -update to write the golden files.
+func TestGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.dbml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+	for _, fixture := range fixtures {
+		text, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := parser.NewParser(explicitparser.NewParser()).ParseText(string(text))
+		if err != nil {
+			t.Fatalf("%s: %s", fixture, err)
+		}
+
+		for _, format := range formats {
+			golden := strings.TrimSuffix(fixture, ".dbml") + "." + format.name + "." + format.extension
+			t.Run(filepath.Base(golden), func(t *testing.T) {
+				exporter, err := export.Lookup(format.name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				output, err := exporter(result.Symbols)
+				if err != nil {
+					output = "error: " + err.Error() + "\n"
+				}
+				if *update {
+					if err := os.WriteFile(golden, []byte(output), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				expected, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(expected) != output {
+					t.Errorf("%s differs from the %s export of %s, run go test -update to accept", golden, format.name, fixture)
+				}
+			})
+		}
+	}
+}
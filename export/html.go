@@ -0,0 +1,142 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+const htmlStyle = `    body { margin: 0; display: flex; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 14px; color: #24292F; }
+    nav { position: sticky; top: 0; height: 100vh; overflow-y: auto; box-sizing: border-box; min-width: 220px; padding: 16px; background: #F6F8FA; border-right: 1px solid #D0D7DE; }
+    nav ul { list-style: none; padding-left: 12px; margin: 4px 0; }
+    nav > ul { padding-left: 0; }
+    main { flex: 1; max-width: 1100px; padding: 16px 32px; }
+    a { color: #0969DA; text-decoration: none; }
+    a:hover { text-decoration: underline; }
+    h2 { border-bottom: 1px solid #D0D7DE; padding-bottom: 4px; margin-top: 40px; }
+    h3 { margin-top: 32px; }
+    table { border-collapse: collapse; margin: 8px 0 16px; }
+    th, td { border: 1px solid #D0D7DE; padding: 4px 10px; text-align: left; vertical-align: top; }
+    th { background: #F6F8FA; }
+    code, .mono { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 13px; }
+    .note { white-space: pre-wrap; }
+    .muted { color: #57606A; }
+`
+
+// HTML renders the data dictionary of storage like Markdown as a
+// single HTML file without external resources, with a navigation
+// of the schemes, tables and enums
+func HTML(storage *symbols.Storage) (string, error) {
+	d := newDictionary(storage)
+	var b strings.Builder
+	title := html.EscapeString(d.title)
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n")
+	b.WriteString("  <meta charset=\"utf-8\">\n")
+	b.WriteString("  <meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&b, "  <title>%s</title>\n", title)
+	fmt.Fprintf(&b, "  <style>\n%s  </style>\n", htmlStyle)
+	b.WriteString("</head>\n<body>\n")
+
+	b.WriteString("<nav>\n  <ul>\n")
+	for _, scheme := range d.schemes {
+		fmt.Fprintf(&b, "    <li><a href=\"#schema-%s\">%s</a>\n      <ul>\n", anchorName(scheme.name), html.EscapeString(scheme.name))
+		for _, docs := range scheme.tables {
+			fmt.Fprintf(&b, "        <li><a href=\"#%s\">%s</a></li>\n", tableAnchor(docs.table), html.EscapeString(docs.table.Name))
+		}
+		for _, enum := range scheme.enums {
+			fmt.Fprintf(&b, "        <li><span class=\"muted\">enum</span> <a href=\"#%s\">%s</a></li>\n", enumAnchor(enum), html.EscapeString(enum.Name))
+		}
+		b.WriteString("      </ul>\n    </li>\n")
+	}
+	if len(d.notes) > 0 {
+		b.WriteString("    <li><a href=\"#notes\">Notes</a></li>\n")
+	}
+	b.WriteString("  </ul>\n</nav>\n")
+
+	b.WriteString("<main>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", title)
+	if d.intro != "" {
+		fmt.Fprintf(&b, "<p class=\"note\">%s</p>\n", html.EscapeString(strings.TrimSpace(d.intro)))
+	}
+	for _, scheme := range d.schemes {
+		fmt.Fprintf(&b, "\n<h2 id=\"schema-%s\">Schema %s</h2>\n", anchorName(scheme.name), html.EscapeString(scheme.name))
+		for _, docs := range scheme.tables {
+			d.htmlTable(&b, docs)
+		}
+		for _, enum := range scheme.enums {
+			htmlEnum(&b, enum)
+		}
+	}
+	if len(d.notes) > 0 {
+		b.WriteString("\n<h2 id=\"notes\">Notes</h2>\n")
+		for _, note := range d.notes {
+			fmt.Fprintf(&b, "<h3>%s</h3>\n<p class=\"note\">%s</p>\n", html.EscapeString(note.Name), html.EscapeString(strings.TrimSpace(note.Content)))
+		}
+	}
+	b.WriteString("</main>\n</body>\n</html>\n")
+	return b.String(), nil
+}
+
+func (d *dictionary) htmlTable(b *strings.Builder, docs *tableDocs) {
+	table := docs.table
+	fmt.Fprintf(b, "\n<h3 id=\"%s\">%s</h3>\n", tableAnchor(table), html.EscapeString(table.Name))
+	if table.Alias != "" {
+		fmt.Fprintf(b, "<p class=\"muted\">Alias: <code>%s</code></p>\n", html.EscapeString(table.Alias))
+	}
+	if table.Note != "" {
+		fmt.Fprintf(b, "<p class=\"note\">%s</p>\n", html.EscapeString(strings.TrimSpace(table.Note)))
+	}
+
+	b.WriteString("<table>\n  <tr><th>Column</th><th>Type</th><th>Settings</th><th>Default</th><th>Note</th></tr>\n")
+	for _, column := range table.Columns {
+		columnType := html.EscapeString(column.Type)
+		if enum, exists := d.storage.EnumByName(column.Type); exists {
+			columnType = fmt.Sprintf("<a href=\"#%s\">%s</a>", enumAnchor(enum), columnType)
+		}
+		defaultValue, _ := column.Setting("default")
+		note, _ := column.Setting("note")
+		fmt.Fprintf(b, "  <tr><td class=\"mono\">%s</td><td class=\"mono\">%s</td><td>%s</td><td class=\"mono\">%s</td><td class=\"note\">%s</td></tr>\n",
+			html.EscapeString(column.Name), columnType, html.EscapeString(strings.Join(columnSettings(column), ", ")),
+			html.EscapeString(defaultValue), html.EscapeString(note))
+	}
+	b.WriteString("</table>\n")
+
+	if len(table.Indexes) > 0 {
+		b.WriteString("<h4>Indexes</h4>\n<table>\n  <tr><th>Columns</th><th>Settings</th><th>Note</th></tr>\n")
+		for _, index := range table.Indexes {
+			columns, settings := indexDescription(index)
+			fmt.Fprintf(b, "  <tr><td class=\"mono\">%s</td><td>%s</td><td class=\"note\">%s</td></tr>\n",
+				html.EscapeString(columns), html.EscapeString(strings.Join(settings, ", ")), html.EscapeString(index.Note))
+		}
+		b.WriteString("</table>\n")
+	}
+	htmlReferences(b, "References", docs.outgoing)
+	htmlReferences(b, "Referenced by", docs.incoming)
+}
+
+func htmlReferences(b *strings.Builder, title string, references []*reference) {
+	if len(references) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "<h4>%s</h4>\n<table>\n  <tr><th>Column</th><th>Relation</th><th>%s</th></tr>\n", title, title)
+	for _, ref := range references {
+		target := html.EscapeString(ref.tableName + "." + ref.target)
+		if ref.table != nil {
+			target = fmt.Sprintf("<a href=\"#%s\">%s</a>", tableAnchor(ref.table), target)
+		}
+		fmt.Fprintf(b, "  <tr><td class=\"mono\">%s</td><td>%s (<code>%s</code>)</td><td class=\"mono\">%s</td></tr>\n",
+			html.EscapeString(ref.column), ref.description, html.EscapeString(ref.relType), target)
+	}
+	b.WriteString("</table>\n")
+}
+
+func htmlEnum(b *strings.Builder, enum *symbols.Enum) {
+	fmt.Fprintf(b, "\n<h3 id=\"%s\">Enum %s</h3>\n", enumAnchor(enum), html.EscapeString(enum.Name))
+	b.WriteString("<table>\n  <tr><th>Value</th><th>Note</th></tr>\n")
+	for _, value := range enum.Values {
+		fmt.Fprintf(b, "  <tr><td class=\"mono\">%s</td><td class=\"note\">%s</td></tr>\n", html.EscapeString(value.Name), html.EscapeString(value.Note))
+	}
+	b.WriteString("</table>\n")
+}
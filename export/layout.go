@@ -0,0 +1,178 @@
+package export
+
+import (
+	"sort"
+)
+
+// box is a node of a layout, a table or a cluster of tables
+type box struct {
+	width, height float64
+}
+
+type point struct {
+	x, y float64
+}
+
+// layout gaps between the layers and between the boxes of a layer
+const (
+	layerGap = 96
+	boxGap   = 32
+)
+
+// layered places boxes in layers from left to right like the Sugiyama
+// method: edges point from a referencing to a referenced box, which is
+// placed in a layer to the left. Cycles are broken by ignoring the edges
+// closing them, the boxes of a layer are ordered by the barycenter of
+// their neighbors to reduce crossings. It returns the top left corner
+// of each box and the size of the layout.
+func layered(boxes []box, edges [][2]int) ([]point, float64, float64) {
+	if len(boxes) == 0 {
+		return nil, 0, 0
+	}
+	ranks := rank(len(boxes), edges)
+	layers := make([][]int, 0)
+	for node, r := range ranks {
+		for len(layers) <= r {
+			layers = append(layers, make([]int, 0))
+		}
+		layers[r] = append(layers[r], node)
+	}
+	order(layers, edges)
+	return place(boxes, layers, edges)
+}
+
+// rank returns the layer of each node, the length of the longest
+// path to a node without outgoing edges
+func rank(nodes int, edges [][2]int) []int {
+	outgoing := make([][]int, nodes)
+	for _, e := range edges {
+		if e[0] != e[1] {
+			outgoing[e[0]] = append(outgoing[e[0]], e[1])
+		}
+	}
+
+	// depth first search, edges to nodes on the stack close a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, nodes)
+	ranks := make([]int, nodes)
+	var visit func(node int)
+	visit = func(node int) {
+		state[node] = visiting
+		for _, target := range outgoing[node] {
+			if state[target] == unvisited {
+				visit(target)
+			}
+			if state[target] == visited && ranks[target]+1 > ranks[node] {
+				ranks[node] = ranks[target] + 1
+			}
+		}
+		state[node] = visited
+	}
+	for node := 0; node < nodes; node++ {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+	return ranks
+}
+
+// order sorts the nodes of each layer by the mean position of their
+// neighbors in the previous layers, sweeping forth and back
+func order(layers [][]int, edges [][2]int) {
+	layerOf := make(map[int]int)
+	position := make(map[int]float64)
+	for l, layer := range layers {
+		for i, node := range layer {
+			layerOf[node] = l
+			position[node] = float64(i)
+		}
+	}
+	neighbors := make(map[int][]int)
+	for _, e := range edges {
+		if e[0] != e[1] {
+			neighbors[e[0]] = append(neighbors[e[0]], e[1])
+			neighbors[e[1]] = append(neighbors[e[1]], e[0])
+		}
+	}
+
+	sweep := func(l int, before func(other int) bool) {
+		layer := layers[l]
+		barycenter := make(map[int]float64, len(layer))
+		for _, node := range layer {
+			sum, count := 0.0, 0
+			for _, neighbor := range neighbors[node] {
+				if before(layerOf[neighbor]) {
+					sum += position[neighbor]
+					count++
+				}
+			}
+			barycenter[node] = position[node]
+			if count > 0 {
+				barycenter[node] = sum / float64(count)
+			}
+		}
+		sort.SliceStable(layer, func(i, j int) bool { return barycenter[layer[i]] < barycenter[layer[j]] })
+		for i, node := range layer {
+			position[node] = float64(i)
+		}
+	}
+	for iteration := 0; iteration < 4; iteration++ {
+		for l := 1; l < len(layers); l++ {
+			sweep(l, func(other int) bool { return other < l })
+		}
+		for l := len(layers) - 2; l >= 0; l-- {
+			sweep(l, func(other int) bool { return other > l })
+		}
+	}
+}
+
+// place assigns the coordinates, each box is moved towards the mean
+// height of its neighbors in the previous layers without overlapping
+func place(boxes []box, layers [][]int, edges [][2]int) ([]point, float64, float64) {
+	points := make([]point, len(boxes))
+	placed := make([]bool, len(boxes))
+	neighbors := make(map[int][]int)
+	for _, e := range edges {
+		neighbors[e[0]] = append(neighbors[e[0]], e[1])
+		neighbors[e[1]] = append(neighbors[e[1]], e[0])
+	}
+
+	x, width, height := 0.0, 0.0, 0.0
+	for _, layer := range layers {
+		layerWidth := 0.0
+		y := 0.0
+		for _, node := range layer {
+			b := boxes[node]
+			// center on the placed neighbors
+			sum, count := 0.0, 0
+			for _, neighbor := range neighbors[node] {
+				if placed[neighbor] {
+					sum += points[neighbor].y + boxes[neighbor].height/2
+					count++
+				}
+			}
+			top := y
+			if count > 0 && sum/float64(count)-b.height/2 > top {
+				top = sum/float64(count) - b.height/2
+			}
+			points[node] = point{x: x, y: top}
+			y = top + b.height + boxGap
+			if b.width > layerWidth {
+				layerWidth = b.width
+			}
+			if top+b.height > height {
+				height = top + b.height
+			}
+		}
+		for _, node := range layer {
+			placed[node] = true
+		}
+		width = x + layerWidth
+		x += layerWidth + layerGap
+	}
+	return points, width, height
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Markdown renders the data dictionary of storage: the project note as
+// introduction, a section per scheme with its tables and enums, and
+// the sticky notes. Tables list their columns, indexes and the refs
+// from and to their columns, which link to the referenced sections.
+func Markdown(storage *symbols.Storage) (string, error) {
+	d := newDictionary(storage)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", markdownText(d.title))
+	if d.intro != "" {
+		b.WriteString(markdownParagraph(d.intro) + "\n\n")
+	}
+
+	b.WriteString("## Contents\n\n")
+	for _, scheme := range d.schemes {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", markdownText(scheme.name), "schema-"+anchorName(scheme.name))
+		for _, docs := range scheme.tables {
+			fmt.Fprintf(&b, "  - [%s](#%s)\n", markdownText(docs.table.Name), tableAnchor(docs.table))
+		}
+		for _, enum := range scheme.enums {
+			fmt.Fprintf(&b, "  - enum [%s](#%s)\n", markdownText(enum.Name), enumAnchor(enum))
+		}
+	}
+	if len(d.notes) > 0 {
+		b.WriteString("- [Notes](#notes)\n")
+	}
+
+	for _, scheme := range d.schemes {
+		fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n## Schema %s\n", "schema-"+anchorName(scheme.name), markdownText(scheme.name))
+		for _, docs := range scheme.tables {
+			d.markdownTable(&b, docs)
+		}
+		for _, enum := range scheme.enums {
+			markdownEnum(&b, enum)
+		}
+	}
+
+	if len(d.notes) > 0 {
+		b.WriteString("\n<a id=\"notes\"></a>\n\n## Notes\n")
+		for _, note := range d.notes {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", markdownText(note.Name), markdownParagraph(note.Content))
+		}
+	}
+	return b.String(), nil
+}
+
+func (d *dictionary) markdownTable(b *strings.Builder, docs *tableDocs) {
+	table := docs.table
+	fmt.Fprintf(b, "\n<a id=\"%s\"></a>\n\n### %s\n\n", tableAnchor(table), markdownText(table.Name))
+	if table.Alias != "" {
+		fmt.Fprintf(b, "Alias: `%s`\n\n", table.Alias)
+	}
+	if table.Note != "" {
+		b.WriteString(markdownParagraph(table.Note) + "\n\n")
+	}
+
+	b.WriteString("| Column | Type | Settings | Default | Note |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, column := range table.Columns {
+		columnType := markdownCell(column.Type)
+		if enum, exists := d.storage.EnumByName(column.Type); exists {
+			columnType = fmt.Sprintf("[%s](#%s)", columnType, enumAnchor(enum))
+		}
+		defaultValue, _ := column.Setting("default")
+		note, _ := column.Setting("note")
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n", markdownCell(column.Name), columnType,
+			markdownCell(strings.Join(columnSettings(column), ", ")), markdownCell(defaultValue), markdownCell(note))
+	}
+
+	if len(table.Indexes) > 0 {
+		b.WriteString("\n**Indexes**\n\n")
+		b.WriteString("| Columns | Settings | Note |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, index := range table.Indexes {
+			columns, settings := indexDescription(index)
+			fmt.Fprintf(b, "| %s | %s | %s |\n", markdownCell(columns), markdownCell(strings.Join(settings, ", ")), markdownCell(index.Note))
+		}
+	}
+	markdownReferences(b, "References", docs.outgoing)
+	markdownReferences(b, "Referenced by", docs.incoming)
+}
+
+func markdownReferences(b *strings.Builder, title string, references []*reference) {
+	if len(references) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n**%s**\n\n", title)
+	fmt.Fprintf(b, "| Column | Relation | %s |\n", title)
+	b.WriteString("| --- | --- | --- |\n")
+	for _, ref := range references {
+		target := markdownCell(ref.tableName + "." + ref.target)
+		if ref.table != nil {
+			target = fmt.Sprintf("[%s](#%s)", target, tableAnchor(ref.table))
+		}
+		fmt.Fprintf(b, "| %s | %s (`%s`) | %s |\n", markdownCell(ref.column), ref.description, ref.relType, target)
+	}
+}
+
+func markdownEnum(b *strings.Builder, enum *symbols.Enum) {
+	fmt.Fprintf(b, "\n<a id=\"%s\"></a>\n\n### Enum %s\n\n", enumAnchor(enum), markdownText(enum.Name))
+	b.WriteString("| Value | Note |\n")
+	b.WriteString("| --- | --- |\n")
+	for _, value := range enum.Values {
+		fmt.Fprintf(b, "| %s | %s |\n", markdownCell(value.Name), markdownCell(value.Note))
+	}
+}
+
+// markdownEscaper escapes the characters markdown renderers
+// pass through as html, notes are shown as text
+var markdownEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownText keeps headings and link texts on one line
+func markdownText(text string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(text), " "))
+}
+
+// markdownParagraph escapes html in notes, their markdown is kept
+func markdownParagraph(text string) string {
+	return markdownEscaper.Replace(strings.TrimSpace(text))
+}
+
+// markdownCell escapes html and the column delimiter
+// and keeps line breaks of table cells
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(markdownParagraph(text), "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Mermaid renders the selected tables as Mermaid erDiagram without
+// clusters and colors, e.g.
+//
+//	erDiagram
+//	  users {
+//	    int id PK
+//	  }
+//	  orders }o--|| users : "user_id"
+func Mermaid(storage *symbols.Storage, options DiagramOptions) (string, error) {
+	d, err := newDiagram(storage, options)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range d.tables {
+		fmt.Fprintf(&b, "  %s {\n", mermaidEntity(table))
+		for _, column := range table.Columns {
+			fmt.Fprintf(&b, "    %s %s", mermaidWord(column.Type), mermaidWord(column.Name))
+			if keys := d.columnKeys(table, column); len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ", "))
+			}
+			if note, exists := column.Setting("note"); exists {
+				fmt.Fprintf(&b, " %s", mermaidString(note))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("  }\n")
+	}
+	for _, e := range d.edges {
+		fmt.Fprintf(&b, "  %s %s %s : %s\n", diagramID(e.from), crowsFoot(e), diagramID(e.to), mermaidString(e.fromColumn))
+	}
+	return b.String(), nil
+}
+
+// mermaidEntity returns the entity name, labeled with
+// the qualified name if the entity name differs
+func mermaidEntity(table *symbols.Table) string {
+	name, label := diagramID(table), diagramLabel(table)
+	if name == label {
+		return name
+	}
+	return name + "[" + mermaidString(label) + "]"
+}
+
+var mermaidNonWord = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+
+// mermaidWord replaces characters that are invalid in attribute
+// types and names, e.g. decimal(10,2) becomes decimal(10-2)
+func mermaidWord(word string) string {
+	word = strings.ReplaceAll(word, ",", "-")
+	word = mermaidNonWord.ReplaceAllString(word, "_")
+	if word == "" || (word[0] >= '0' && word[0] <= '9') {
+		word = "_" + word
+	}
+	return word
+}
+
+// mermaidString quotes comments and labels, which
+// can not contain double quotes or line breaks
+func mermaidString(value string) string {
+	value = strings.ReplaceAll(value, `"`, "'")
+	return `"` + strings.Join(strings.Fields(value), " ") + `"`
+}
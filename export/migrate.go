@@ -0,0 +1,608 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/diff"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Migrate returns the statements migrating a database from the old to
+// the new schema of changes. Foreign keys are dropped first and added
+// last, so that tables are created before they are referenced and
+// dropped after all references are gone. Destructive changes like
+// dropped columns are flagged with a warning, which is also written
+// as comment above the statement.
+func Migrate(changes *diff.Diff, old *symbols.Storage, new *symbols.Storage, dialect *Dialect) (string, []string, error) {
+	m := &migration{
+		changes: changes,
+		dialect: dialect,
+		old:     &sqlWriter{storage: old, dialect: dialect},
+		new:     &sqlWriter{storage: new, dialect: dialect},
+	}
+	steps := []func() error{
+		m.dropForeignKeys,
+		m.dropIndexes,
+		m.renameTables,
+		m.createEnums,
+		m.createTables,
+		m.alterTables,
+		m.createIndexes,
+		m.addForeignKeys,
+		m.dropTables,
+		m.dropEnums,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return "", m.warnings, err
+		}
+	}
+	if len(m.statements) == 0 {
+		return "-- no changes\n", m.warnings, nil
+	}
+	return strings.Join(m.statements, "\n\n") + "\n", m.warnings, nil
+}
+
+type migration struct {
+	changes *diff.Diff
+	dialect *Dialect
+	// old and new quote and map the symbols of each schema
+	old        *sqlWriter
+	new        *sqlWriter
+	statements []string
+	warnings   []string
+	// junctions are the junction tables of added many-to-many refs
+	junctions []*symbols.Table
+}
+
+func (m *migration) add(format string, args ...any) {
+	m.statements = append(m.statements, fmt.Sprintf(format, args...))
+}
+
+// warn records a warning and adds it as comment
+func (m *migration) warn(format string, args ...any) {
+	warning := fmt.Sprintf(format, args...)
+	m.warnings = append(m.warnings, warning)
+	m.add("-- WARNING: %s", warning)
+}
+
+// flush moves the statements and warnings of the new schema to the migration
+func (m *migration) flush() {
+	m.statements = append(m.statements, m.new.statements...)
+	m.warnings = append(m.warnings, m.new.warnings...)
+	m.new.statements = nil
+	m.new.warnings = nil
+}
+
+// definition returns the definition of a column of the new schema
+// without primary key, warnings about it are added to the migration
+func (m *migration) definition(table *symbols.Table, column *symbols.Column) string {
+	definition := m.new.columnDefinition(table, column, false)
+	m.flush()
+	return definition
+}
+
+func (m *migration) tables(changes ...diff.Change) []*diff.TableDiff {
+	tables := make([]*diff.TableDiff, 0)
+	for _, table := range m.changes.Tables {
+		for _, change := range changes {
+			if table.Change == change {
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables
+}
+
+func (m *migration) relationships(changes ...diff.Change) []*diff.RelationshipDiff {
+	relationships := make([]*diff.RelationshipDiff, 0)
+	for _, rel := range m.changes.Relationships {
+		for _, change := range changes {
+			if rel.Change == change {
+				relationships = append(relationships, rel)
+			}
+		}
+	}
+	return relationships
+}
+
+// removedTable reports whether the table of the
+// old schema is dropped by the migration
+func (m *migration) removedTable(scheme string, name string) bool {
+	qualified := symbols.QualifiedName(scheme, name)
+	for _, table := range m.tables(diff.Removed) {
+		if table.Name == qualified {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKeyName returns the name of key, unnamed keys get the
+// name the database generates or a name derived the same way
+func (m *migration) foreignKeyName(key foreignKey) (string, bool) {
+	if key.name != "" {
+		return key.name, true
+	}
+	if m.dialect.ForeignKeyName != nil {
+		return m.dialect.ForeignKeyName(key.from.name, key.from.column), true
+	}
+	return key.from.name + "_" + key.from.column + "_fkey", false
+}
+
+// dropForeignKeys drops the foreign keys of removed and altered refs,
+// the keys of dropped tables are dropped with the table
+func (m *migration) dropForeignKeys() error {
+	for _, rel := range m.relationships(diff.Removed, diff.Altered) {
+		oldRel := rel.Relationship
+		if rel.Change == diff.Altered {
+			oldRel = rel.Old
+		}
+		w := &sqlWriter{storage: m.old.storage, dialect: m.dialect}
+		if junction := w.relationship(oldRel); junction != nil {
+			// dropped with the junction table in dropTables
+			continue
+		}
+		for _, key := range w.foreignKeys {
+			if m.removedTable(key.from.scheme, key.from.name) {
+				continue
+			}
+			if m.dialect.DropForeignKey == "" {
+				m.warn("%s can not drop the foreign key %s -> %s, the table has to be rebuilt", m.dialect.Name, key.from, key.to)
+				continue
+			}
+			name, known := m.foreignKeyName(key)
+			if !known {
+				m.warn("the name of the foreign key %s -> %s is unknown, %s is assumed", key.from, key.to, name)
+			}
+			m.add("ALTER TABLE %s %s %s;", m.old.tableIdent(key.from.scheme, key.from.name), m.dialect.DropForeignKey, m.dialect.Quote(name))
+		}
+	}
+	return nil
+}
+
+// indexName returns the name of an existing index
+func (m *migration) indexName(table *symbols.Table, index *symbols.Index) string {
+	switch {
+	case index.Name != "":
+		return index.Name
+	case m.dialect.IndexNames:
+		return indexName(table, index)
+	}
+	// PostgreSQL names indexes like table_column_idx
+	name := strings.TrimSuffix(indexName(table, index), "_index")
+	name = strings.TrimSuffix(name, "_unique")
+	if index.Unique {
+		name += "_key"
+	} else {
+		name += "_idx"
+	}
+	m.warn("the index %s of %s has no name, %s is assumed", diff.IndexName(index), table.Name, name)
+	return name
+}
+
+func (m *migration) dropIndexes() error {
+	for _, table := range m.tables(diff.Altered, diff.Renamed) {
+		for _, index := range table.Indexes {
+			oldIndex := index.Old
+			if index.Change == diff.Removed {
+				oldIndex = index.Index
+			}
+			if index.Change == diff.Added || (!m.dialect.IndexExpressions && hasExpression(oldIndex)) {
+				continue
+			}
+			if oldIndex.PrimaryKey {
+				m.warn("the primary key of %s changed and has to be migrated manually", table.Name)
+				continue
+			}
+			name := m.indexName(table.Old, oldIndex)
+			m.add("%s", m.dialect.DropIndex(m.dialect, tableName{table.Old.Scheme, table.Old.Name}, name))
+		}
+	}
+	return nil
+}
+
+func (m *migration) renameTables() error {
+	for _, table := range m.tables(diff.Renamed) {
+		from := tableName{table.Old.Scheme, table.Old.Name}
+		to := tableName{table.Table.Scheme, table.Table.Name}
+		if from.scheme != to.scheme && m.dialect.CreateSchema != nil {
+			m.add("%s", m.dialect.CreateSchema(sqlScheme(to.scheme, symbols.DefaultScheme)))
+		}
+		m.add("%s", m.dialect.RenameTable(m.dialect, from, to))
+	}
+	return nil
+}
+
+func (m *migration) createEnums() error {
+	for _, enum := range m.changes.Enums {
+		switch {
+		case enum.Change == diff.Added && m.dialect.Enums == EnumType:
+			m.createSchema(enum.Enum.Scheme)
+			m.add("%s", m.new.createEnum(enum.Enum))
+		case enum.Change == diff.Altered && m.dialect.Enums == EnumType:
+			for _, value := range enum.AddedValues {
+				m.add("ALTER TYPE %s ADD VALUE %s;", m.new.tableIdent(enum.Enum.Scheme, enum.Enum.Name), quoteString(value))
+			}
+			if len(enum.RemovedValues) > 0 {
+				m.warn("values can not be removed from the enum %s: %s", enum.Name, strings.Join(enum.RemovedValues, ", "))
+			}
+		case enum.Change == diff.Altered && m.dialect.Enums == EnumCheck:
+			m.warn("the CHECK constraints of columns using the enum %s have to be updated", enum.Name)
+		}
+	}
+	return nil
+}
+
+// createSchema creates scheme if no table of the old schema uses it
+func (m *migration) createSchema(scheme string) {
+	if m.dialect.CreateSchema == nil || scheme == "" || scheme == symbols.DefaultScheme {
+		return
+	}
+	for _, existing := range m.old.storage.Schemes() {
+		if existing == scheme {
+			return
+		}
+	}
+	if statement := m.dialect.CreateSchema(scheme); !m.added(statement) {
+		m.add("%s", statement)
+	}
+}
+
+// added reports whether the migration already contains statement
+func (m *migration) added(statement string) bool {
+	for _, added := range m.statements {
+		if added == statement {
+			return true
+		}
+	}
+	return false
+}
+
+// createTables creates the added tables and the junction tables of added
+// many-to-many refs, dialects without ALTER TABLE ADD FOREIGN KEY
+// declare the keys of new tables in CREATE TABLE
+func (m *migration) createTables() error {
+	for _, rel := range m.relationships(diff.Added, diff.Altered) {
+		if junction := m.new.relationship(rel.Relationship); junction != nil {
+			m.junctions = append(m.junctions, junction)
+		}
+	}
+	for _, table := range m.tables(diff.Added) {
+		m.createSchema(table.Table.Scheme)
+		m.new.table(table.Table)
+		m.new.comments(table.Table)
+	}
+	for _, junction := range m.junctions {
+		m.new.table(junction)
+	}
+	m.flush()
+	return nil
+}
+
+func (m *migration) alterTables() error {
+	for _, table := range m.tables(diff.Altered, diff.Renamed) {
+		ident := m.new.tableIdent(table.Table.Scheme, table.Table.Name)
+		for _, column := range table.Columns {
+			switch column.Change {
+			case diff.Added:
+				if column.Column.Has("not null") && !hasDefault(column.Column) {
+					m.warn("%s.%s is added as NOT NULL without default, this fails for tables with rows", table.Table.Name, column.Name)
+				}
+				definition := m.definition(table.Table, column.Column)
+				if check := m.new.enumCheck(column.Column); check != "" {
+					// SQLite can not add constraints to existing tables
+					definition += " " + check
+				}
+				m.add("ALTER TABLE %s %s %s;", ident, m.dialect.AddColumn, definition)
+			case diff.Altered:
+				m.alterColumn(table, column)
+			case diff.Removed:
+				m.warn("dropping the column %s.%s deletes its data", table.Table.Name, column.Name)
+				m.add("ALTER TABLE %s DROP COLUMN %s;", ident, m.dialect.Quote(column.Name))
+			}
+		}
+		if table.Note != nil {
+			m.tableNote(table)
+		}
+	}
+	m.modifyEnumColumns()
+	return nil
+}
+
+func hasDefault(column *symbols.Column) bool {
+	_, exists := column.Setting("default")
+	return exists || column.Has("increment")
+}
+
+func (m *migration) alterColumn(table *diff.TableDiff, column *diff.ColumnDiff) {
+	dialect := m.dialect
+	ident := m.new.tableIdent(table.Table.Scheme, table.Table.Name)
+	name := dialect.Quote(column.Name)
+	changed := make(map[string]diff.Value)
+	for _, attribute := range column.Attributes {
+		changed[attribute.Name] = attribute.Value
+	}
+	if value, exists := changed["type"]; exists {
+		m.checkType(table.Table.Name, column.Name, value)
+	}
+	if value, exists := changed["nullable"]; exists && value.New == "not null" {
+		m.warn("%s.%s becomes NOT NULL, this fails for rows with NULL values", table.Table.Name, column.Name)
+	}
+	for _, attribute := range []string{"pk", "increment"} {
+		if _, exists := changed[attribute]; exists {
+			m.warn("the %s setting of %s.%s changed and has to be migrated manually", attribute, table.Table.Name, column.Name)
+		}
+	}
+	if value, exists := changed["unique"]; exists {
+		if value.New == "true" {
+			m.add("ALTER TABLE %s ADD UNIQUE (%s);", ident, name)
+		} else {
+			m.warn("the unique constraint of %s.%s has to be dropped manually", table.Table.Name, column.Name)
+		}
+	}
+
+	_, typeChanged := changed["type"]
+	_, nullChanged := changed["nullable"]
+	_, defaultChanged := changed["default"]
+	_, noteChanged := changed["note"]
+	if !typeChanged && !nullChanged && !defaultChanged && !noteChanged {
+		return
+	}
+	switch dialect.AlterColumn {
+	case AlterClauses:
+		if typeChanged {
+			m.add("ALTER TABLE %s ALTER COLUMN %s TYPE %s;", ident, name, m.new.columnType(column.Column.Type))
+		}
+		if nullChanged && column.Column.Has("not null") {
+			m.add("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", ident, name)
+		} else if nullChanged {
+			m.add("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", ident, name)
+		}
+		if value, exists := column.Column.Setting("default"); defaultChanged && exists {
+			m.add("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", ident, name, m.new.defaultValue(value))
+		} else if defaultChanged {
+			m.add("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", ident, name)
+		}
+	case AlterModify:
+		// the note is part of the column definition
+		m.add("ALTER TABLE %s MODIFY COLUMN %s;", ident, m.definition(table.Table, column.Column))
+		return
+	case AlterDefinition:
+		if typeChanged || nullChanged {
+			nullable := " NULL"
+			if column.Column.Has("not null") {
+				nullable = " NOT NULL"
+			}
+			m.add("ALTER TABLE %s ALTER COLUMN %s %s%s;", ident, name, m.new.columnType(column.Column.Type), nullable)
+		}
+		if value, exists := column.Column.Setting("default"); defaultChanged && exists {
+			if value := changed["default"]; value.Old != "" {
+				m.warn("the previous default constraint of %s.%s has to be dropped first", table.Table.Name, column.Name)
+			}
+			m.add("ALTER TABLE %s ADD DEFAULT %s FOR %s;", ident, m.new.defaultValue(value), name)
+		} else if defaultChanged {
+			m.warn("the default constraint of %s.%s has to be dropped manually", table.Table.Name, column.Name)
+		}
+	case AlterUnsupported:
+		if typeChanged || nullChanged || defaultChanged {
+			m.warn("%s can not alter the column %s.%s, the table has to be rebuilt", dialect.Name, table.Table.Name, column.Name)
+		}
+	}
+	if note, _ := column.Column.Setting("note"); noteChanged && dialect.ColumnComment != nil {
+		m.add("%s", dialect.ColumnComment(dialect, table.Table.Scheme, table.Table.Name, column.Name, note))
+	}
+}
+
+func (m *migration) tableNote(table *diff.TableDiff) {
+	switch {
+	case m.dialect.InlineComments:
+		m.add("ALTER TABLE %s COMMENT=%s;", m.new.tableIdent(table.Table.Scheme, table.Table.Name), quoteString(table.Note.New))
+	case m.dialect.TableComment != nil:
+		m.add("%s", m.dialect.TableComment(m.dialect, table.Table.Scheme, table.Table.Name, table.Note.New))
+	}
+}
+
+// modifyEnumColumns restates the columns using altered
+// enums for dialects declaring the values in the column
+func (m *migration) modifyEnumColumns() {
+	if m.dialect.Enums != EnumInline {
+		return
+	}
+	for _, enum := range m.changes.Enums {
+		if enum.Change != diff.Altered {
+			continue
+		}
+		if len(enum.RemovedValues) > 0 {
+			m.warn("rows with the removed values of %s (%s) fail the column change", enum.Name, strings.Join(enum.RemovedValues, ", "))
+		}
+		for _, table := range m.new.storage.Tables() {
+			for _, column := range table.Columns {
+				if !usesEnum(m.new.storage, column, enum.Enum) {
+					continue
+				}
+				statement := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", m.new.tableIdent(table.Scheme, table.Name), m.definition(table, column))
+				if !m.added(statement) {
+					m.add("%s", statement)
+				}
+			}
+		}
+	}
+}
+
+func usesEnum(storage *symbols.Storage, column *symbols.Column, enum *symbols.Enum) bool {
+	columnEnum, exists := storage.EnumByName(column.Type)
+	return exists && columnEnum == enum
+}
+
+func (m *migration) createIndexes() error {
+	for _, table := range m.tables(diff.Added) {
+		m.new.indexes(table.Table)
+	}
+	for _, table := range m.tables(diff.Altered, diff.Renamed) {
+		for _, index := range table.Indexes {
+			if index.Change != diff.Removed && !index.Index.PrimaryKey {
+				m.new.add("%s", m.new.createIndex(table.Table, index.Index))
+			}
+		}
+	}
+	m.flush()
+	return nil
+}
+
+// addForeignKeys adds the foreign keys collected in createTables,
+// keys of new tables are declared inline by some dialects
+func (m *migration) addForeignKeys() error {
+	for _, key := range m.new.foreignKeys {
+		if m.dialect.InlineForeignKeys {
+			if !m.createdTable(key.from) {
+				m.warn("%s can not add the foreign key %s -> %s, the table has to be rebuilt", m.dialect.Name, key.from, key.to)
+			}
+			continue
+		}
+		key.name, _ = m.foreignKeyName(key)
+		m.add("ALTER TABLE %s ADD %s;", m.new.tableIdent(key.from.scheme, key.from.name), m.new.foreignKey(key))
+	}
+	return nil
+}
+
+// createdTable reports whether the table of e is created by the migration
+func (m *migration) createdTable(e endpoint) bool {
+	for _, table := range m.tables(diff.Added) {
+		if table.Table.Name == e.name && table.Table.Scheme == e.scheme {
+			return true
+		}
+	}
+	for _, junction := range m.junctions {
+		if junction.Name == e.name && junction.Scheme == e.scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// dropTables drops removed tables and the junction tables of removed
+// many-to-many refs, tables referencing other dropped tables first
+func (m *migration) dropTables() error {
+	dropped := make([]*symbols.Table, 0)
+	for _, table := range m.tables(diff.Removed) {
+		dropped = append(dropped, table.Table)
+	}
+	for _, rel := range m.relationships(diff.Removed) {
+		if rel.Relationship.Type != "<>" {
+			continue
+		}
+		w := &sqlWriter{storage: m.old.storage, dialect: m.dialect}
+		if junction := w.relationship(rel.Relationship); junction != nil {
+			dropped = append([]*symbols.Table{junction}, dropped...)
+		}
+	}
+
+	for len(dropped) > 0 {
+		next := 0
+		for i, table := range dropped {
+			if !m.referenced(table, dropped) {
+				next = i
+				break
+			}
+		}
+		table := dropped[next]
+		dropped = append(dropped[:next], dropped[next+1:]...)
+		m.warn("dropping the table %s deletes its data", table.Name)
+		m.add("DROP TABLE %s;", m.old.tableIdent(table.Scheme, table.Name))
+	}
+	return nil
+}
+
+// referenced reports whether another table of tables references table
+func (m *migration) referenced(table *symbols.Table, tables []*symbols.Table) bool {
+	w := &sqlWriter{storage: m.old.storage, dialect: m.dialect}
+	for _, other := range tables {
+		if other == table {
+			continue
+		}
+		for _, rel := range other.References {
+			w.foreignKeys = nil
+			w.relationship(rel)
+			for _, key := range w.foreignKeys {
+				if key.to.name == table.Name && key.to.scheme == table.Scheme && key.from.name != table.Name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (m *migration) dropEnums() error {
+	if m.dialect.Enums != EnumType {
+		return nil
+	}
+	for _, enum := range m.changes.Enums {
+		if enum.Change == diff.Removed {
+			m.add("DROP TYPE %s;", m.old.tableIdent(enum.Enum.Scheme, enum.Enum.Name))
+		}
+	}
+	return nil
+}
+
+// checkType warns about type changes that can lose data: smaller
+// integer types, shorter lengths and precisions and unrelated types
+func (m *migration) checkType(table string, column string, value diff.Value) {
+	oldBase, oldArgs := splitType(value.Old)
+	newBase, newArgs := splitType(value.New)
+	oldRank, oldInteger := integerRanks[oldBase]
+	newRank, newInteger := integerRanks[newBase]
+	switch {
+	case oldInteger && newInteger:
+		if newRank < oldRank {
+			m.warn("%s.%s narrows from %s to %s", table, column, value.Old, value.New)
+		}
+	case oldBase == newBase || (textTypes[oldBase] && textTypes[newBase]):
+		if narrowerArgs(oldArgs, newArgs) {
+			m.warn("%s.%s narrows from %s to %s", table, column, value.Old, value.New)
+		}
+	default:
+		m.warn("%s.%s changes from %s to %s, values might not convert", table, column, value.Old, value.New)
+	}
+}
+
+var integerRanks = map[string]int{
+	"tinyint": 1, "smallint": 2, "int2": 2, "mediumint": 3,
+	"int": 4, "integer": 4, "int4": 4, "bigint": 5, "int8": 5,
+}
+
+// textTypes are unbounded without length
+var textTypes = map[string]bool{
+	"text": true, "varchar": true, "char": true, "nvarchar": true, "string": true,
+}
+
+func splitType(columnType string) (string, []int) {
+	base, args, _ := strings.Cut(columnType, "(")
+	numbers := make([]int, 0, 2)
+	for _, arg := range strings.Split(strings.TrimSuffix(args, ")"), ",") {
+		if number, err := strconv.Atoi(strings.TrimSpace(arg)); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(base)), numbers
+}
+
+// narrowerArgs reports whether the length, precision or scale
+// decreases, types without length are treated as unbounded
+func narrowerArgs(old []int, new []int) bool {
+	if len(new) == 0 {
+		return false
+	}
+	if len(old) == 0 {
+		return true
+	}
+	for i := range new {
+		if i < len(old) && new[i] < old[i] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// PlantUML renders the selected tables as PlantUML entity diagram in
+// information engineering notation, clusters become packages. Primary
+// key columns are listed above the separator, '*' marks not null.
+func PlantUML(storage *symbols.Storage, options DiagramOptions) (string, error) {
+	d, err := newDiagram(storage, options)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	b.WriteString("hide circle\n")
+	b.WriteString("skinparam linetype ortho\n")
+
+	clusters, unclustered := d.clusters()
+	for _, c := range clusters {
+		fmt.Fprintf(&b, "\npackage %s ", plantUMLString(c.name))
+		if c.color != "" && options.HeaderColors {
+			b.WriteString(c.color + " ")
+		}
+		b.WriteString("{\n")
+		for _, table := range c.tables {
+			d.plantUMLEntity(&b, "  ", table)
+		}
+		b.WriteString("}\n")
+	}
+	for _, table := range unclustered {
+		b.WriteString("\n")
+		d.plantUMLEntity(&b, "", table)
+	}
+
+	if len(d.edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, e := range d.edges {
+		fmt.Fprintf(&b, "%s %s %s : %s\n", diagramID(e.from), crowsFoot(e), diagramID(e.to), plantUMLLabel(e.fromColumn))
+	}
+	b.WriteString("@enduml\n")
+	return b.String(), nil
+}
+
+func (d *diagram) plantUMLEntity(b *strings.Builder, indent string, table *symbols.Table) {
+	fmt.Fprintf(b, "%sentity %s as %s ", indent, plantUMLString(diagramLabel(table)), diagramID(table))
+	if color := d.headerColor(table); color != "" {
+		b.WriteString(color + " ")
+	}
+	b.WriteString("{\n")
+
+	keys := make([]*symbols.Column, 0)
+	columns := make([]*symbols.Column, 0, len(table.Columns))
+	primaryKey := primaryKeyColumns(table)
+	for _, column := range table.Columns {
+		if contains(primaryKey, column.Name) {
+			keys = append(keys, column)
+		} else {
+			columns = append(columns, column)
+		}
+	}
+	for _, column := range keys {
+		d.plantUMLColumn(b, indent+"  ", table, column)
+	}
+	if len(keys) > 0 && len(columns) > 0 {
+		b.WriteString(indent + "  --\n")
+	}
+	for _, column := range columns {
+		d.plantUMLColumn(b, indent+"  ", table, column)
+	}
+	b.WriteString(indent + "}\n")
+}
+
+func (d *diagram) plantUMLColumn(b *strings.Builder, indent string, table *symbols.Table, column *symbols.Column) {
+	b.WriteString(indent)
+	if column.Has("not null") || column.IsPrimaryKey() {
+		b.WriteString("* ")
+	}
+	fmt.Fprintf(b, "%s : %s", plantUMLLabel(column.Name), plantUMLLabel(column.Type))
+	for _, key := range d.columnKeys(table, column) {
+		fmt.Fprintf(b, " <<%s>>", key)
+	}
+	b.WriteString("\n")
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// plantUMLString quotes names, which can not contain double quotes
+func plantUMLString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+// plantUMLLabel removes line breaks from member and edge labels
+func plantUMLLabel(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
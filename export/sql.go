@@ -0,0 +1,480 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// SQL returns the DDL in the dialect of the database type declared
+// by the project, documents without database type use PostgreSQL
+func SQL(storage *symbols.Storage) (string, error) {
+	dialect, err := ProjectDialect(storage)
+	if err != nil {
+		return "", err
+	}
+	return Generate(storage, dialect)
+}
+
+// ProjectDialect returns the dialect of the database type declared
+// by the project, PostgreSQL for documents without database type
+func ProjectDialect(storage *symbols.Storage) (*Dialect, error) {
+	project := storage.GetProject()
+	if project == nil {
+		return PostgresDialect, nil
+	}
+	databaseType, known := project.DatabaseType()
+	if !known {
+		return PostgresDialect, nil
+	}
+	dialect, exists := DialectOf(databaseType)
+	if !exists {
+		return nil, fmt.Errorf("sql export does not support %s", databaseType)
+	}
+	return dialect, nil
+}
+
+// Generate returns the DDL creating the schemas, enums, tables,
+// indexes, comments and foreign keys declared in storage.
+// Many-to-many refs create a junction table, refs to undeclared tables
+// or columns are skipped with a warning comment.
+func Generate(storage *symbols.Storage, dialect *Dialect) (string, error) {
+	w := &sqlWriter{storage: storage, dialect: dialect}
+	tables := append(storage.Tables(), w.relationships()...)
+
+	w.schemas()
+	w.enums()
+	for _, table := range tables {
+		w.table(table)
+	}
+	for _, table := range tables {
+		w.indexes(table)
+	}
+	for _, table := range tables {
+		w.comments(table)
+	}
+	if !dialect.InlineForeignKeys {
+		for _, key := range w.foreignKeys {
+			w.add("ALTER TABLE %s ADD %s;", w.tableIdent(key.from.scheme, key.from.name), w.foreignKey(key))
+		}
+	}
+	return strings.Join(w.statements, "\n\n") + "\n", nil
+}
+
+// Exporter returns the exporter generating the DDL of dialect
+func (d *Dialect) Exporter() Exporter {
+	return func(storage *symbols.Storage) (string, error) {
+		return Generate(storage, d)
+	}
+}
+
+type sqlWriter struct {
+	storage     *symbols.Storage
+	dialect     *Dialect
+	statements  []string
+	warnings    []string
+	foreignKeys []foreignKey
+}
+
+// foreignKey references to from the column of from
+type foreignKey struct {
+	name string
+	from endpoint
+	to   endpoint
+}
+
+func (w *sqlWriter) add(format string, args ...any) {
+	w.statements = append(w.statements, fmt.Sprintf(format, args...))
+}
+
+// warn records a warning and adds it as comment
+func (w *sqlWriter) warn(format string, args ...any) {
+	warning := fmt.Sprintf(format, args...)
+	w.warnings = append(w.warnings, warning)
+	w.add("-- WARNING: %s", warning)
+}
+
+func (w *sqlWriter) tableIdent(scheme string, name string) string {
+	return w.dialect.qualified(scheme, name)
+}
+
+func (w *sqlWriter) schemas() {
+	if w.dialect.CreateSchema == nil {
+		return
+	}
+	seen := map[string]bool{symbols.DefaultScheme: true}
+	schemes := make([]string, 0)
+	for _, table := range w.storage.Tables() {
+		schemes = append(schemes, table.SchemeOrDefault())
+	}
+	for _, enum := range w.storage.Enums() {
+		schemes = append(schemes, enum.SchemeOrDefault())
+	}
+	for _, scheme := range schemes {
+		if !seen[scheme] {
+			seen[scheme] = true
+			w.add("%s", w.dialect.CreateSchema(scheme))
+		}
+	}
+}
+
+func (w *sqlWriter) enums() {
+	if w.dialect.Enums != EnumType {
+		return
+	}
+	for _, enum := range w.storage.Enums() {
+		w.add("%s", w.createEnum(enum))
+	}
+}
+
+func (w *sqlWriter) createEnum(enum *symbols.Enum) string {
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", w.tableIdent(enum.Scheme, enum.Name), enumValues(enum))
+}
+
+func (w *sqlWriter) table(table *symbols.Table) {
+	dialect := w.dialect
+	primaryKey := primaryKeyColumns(table)
+	lines := make([]string, 0, len(table.Columns)+1)
+	checks := make([]string, 0)
+	for _, column := range table.Columns {
+		lines = append(lines, "  "+w.columnDefinition(table, column, len(primaryKey) == 1))
+		if check := w.enumCheck(column); check != "" {
+			checks = append(checks, "  "+check)
+		}
+	}
+	if len(primaryKey) > 1 {
+		lines = append(lines, "  PRIMARY KEY ("+w.joinColumns(primaryKey)+")")
+	}
+	lines = append(lines, checks...)
+	if dialect.InlineForeignKeys {
+		for _, key := range w.foreignKeys {
+			if key.from.name == table.Name && key.from.scheme == table.Scheme {
+				lines = append(lines, "  "+w.foreignKey(key))
+			}
+		}
+	}
+
+	options := ""
+	if table.Note != "" && dialect.InlineComments {
+		options = " COMMENT=" + quoteString(table.Note)
+	}
+	w.add("CREATE TABLE %s (\n%s\n)%s;", w.tableIdent(table.Scheme, table.Name), strings.Join(lines, ",\n"), options)
+}
+
+// columnDefinition returns the column name, type and constraints,
+// singlePrimaryKey is set if the primary key is not composite
+func (w *sqlWriter) columnDefinition(table *symbols.Table, column *symbols.Column, singlePrimaryKey bool) string {
+	dialect := w.dialect
+	columnType := w.columnType(column.Type)
+	increment := column.Has("increment")
+	if increment && dialect.IncrementPrimaryKey {
+		if singlePrimaryKey && column.IsPrimaryKey() {
+			// the increment is only allowed for INTEGER PRIMARY KEY
+			columnType = "INTEGER"
+		} else {
+			w.warn("%s only increments a single column primary key, %s.%s is not incremented", dialect.Name, table.Name, column.Name)
+			increment = false
+		}
+	}
+	definition := dialect.Quote(column.Name) + " " + columnType
+	if increment {
+		definition += " " + dialect.Increment
+	}
+	if singlePrimaryKey && column.IsPrimaryKey() && !(increment && dialect.IncrementPrimaryKey) {
+		definition += " PRIMARY KEY"
+	}
+	if column.Has("not null") {
+		definition += " NOT NULL"
+	}
+	if column.Has("unique") {
+		definition += " UNIQUE"
+	}
+	if value, exists := column.Setting("default"); exists {
+		definition += " DEFAULT " + w.defaultValue(value)
+	}
+	if note, exists := column.Setting("note"); exists && dialect.InlineComments {
+		definition += " COMMENT " + quoteString(note)
+	}
+	return definition
+}
+
+// enumCheck returns the CHECK constraint of enum columns
+// for EnumCheck dialects, empty for other columns
+func (w *sqlWriter) enumCheck(column *symbols.Column) string {
+	enum, exists := w.storage.EnumByName(column.Type)
+	if !exists || w.dialect.Enums != EnumCheck {
+		return ""
+	}
+	return fmt.Sprintf("CHECK (%s IN (%s))", w.dialect.Quote(column.Name), enumValues(enum))
+}
+
+// columnType declares enums in the style of the dialect
+// and maps all other types with the dialect
+func (w *sqlWriter) columnType(columnType string) string {
+	enum, exists := w.storage.EnumByName(columnType)
+	if !exists {
+		return w.dialect.columnType(columnType)
+	}
+	switch w.dialect.Enums {
+	case EnumInline:
+		return "ENUM(" + enumValues(enum) + ")"
+	case EnumCheck:
+		return w.dialect.EnumBase
+	}
+	return w.tableIdent(enum.Scheme, enum.Name)
+}
+
+// defaultValue converts the dbml source of a default value to sql:
+// strings and colors are quoted, expressions wrapped in parentheses
+func (w *sqlWriter) defaultValue(value string) string {
+	switch {
+	case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) > 1:
+		return quoteString(strings.ReplaceAll(value[1:len(value)-1], "\\'", "'"))
+	case strings.HasPrefix(value, "`") && strings.HasSuffix(value, "`") && len(value) > 1:
+		return "(" + value[1:len(value)-1] + ")"
+	case strings.HasPrefix(value, "#"):
+		return quoteString(value)
+	case value == "true" && !w.dialect.Booleans:
+		return "1"
+	case value == "false" && !w.dialect.Booleans:
+		return "0"
+	}
+	return value
+}
+
+func (w *sqlWriter) indexes(table *symbols.Table) {
+	for _, index := range table.Indexes {
+		if !index.PrimaryKey {
+			w.add("%s", w.createIndex(table, index))
+		}
+	}
+}
+
+// createIndex returns the CREATE INDEX statement or a comment
+// if the dialect does not support expression indexes
+func (w *sqlWriter) createIndex(table *symbols.Table, index *symbols.Index) string {
+	dialect := w.dialect
+	if !dialect.IndexExpressions && hasExpression(index) {
+		return fmt.Sprintf("-- %s does not support expression indexes: %s (%s)",
+			dialect.Name, table.Name, strings.Join(index.Columns, ", "))
+	}
+
+	statement := "CREATE "
+	if index.Unique {
+		statement += "UNIQUE "
+	}
+	statement += "INDEX "
+	name := index.Name
+	if name == "" && dialect.IndexNames {
+		name = indexName(table, index)
+	}
+	if name != "" {
+		statement += dialect.Quote(name) + " "
+	}
+	statement += "ON " + w.tableIdent(table.Scheme, table.Name)
+	if index.Type != "" && dialect.IndexType == IndexTypeBefore {
+		statement += " USING " + strings.ToUpper(index.Type)
+	}
+	statement += " (" + w.joinIndexColumns(index.Columns) + ")"
+	if index.Type != "" && dialect.IndexType == IndexTypeAfter {
+		statement += " USING " + strings.ToUpper(index.Type)
+	}
+	return statement + ";"
+}
+
+func (w *sqlWriter) comments(table *symbols.Table) {
+	dialect := w.dialect
+	if table.Note != "" && dialect.TableComment != nil {
+		w.add("%s", dialect.TableComment(dialect, table.Scheme, table.Name, table.Note))
+	}
+	for _, column := range table.Columns {
+		if note, exists := column.Setting("note"); exists && dialect.ColumnComment != nil {
+			w.add("%s", dialect.ColumnComment(dialect, table.Scheme, table.Name, column.Name, note))
+		}
+	}
+}
+
+// relationships collects a foreign key for every ref and
+// returns the junction tables of many-to-many refs
+func (w *sqlWriter) relationships() []*symbols.Table {
+	junctions := make([]*symbols.Table, 0)
+	for _, rel := range allRelationships(w.storage) {
+		if junction := w.relationship(rel); junction != nil {
+			junctions = append(junctions, junction)
+		}
+	}
+	return junctions
+}
+
+// relationship adds the foreign keys of rel, many-to-many refs return
+// their junction table. Refs between columns that are not declared
+// are skipped with a warning.
+func (w *sqlWriter) relationship(rel *symbols.Relationship) *symbols.Table {
+	left := w.endpoint(rel.SchemeA, rel.TableA, rel.ColumnA)
+	right := w.endpoint(rel.SchemeB, rel.TableB, rel.ColumnB)
+	for _, side := range []endpoint{left, right} {
+		if side.table == nil {
+			w.warn("skipped the ref %s %s %s, table %s is not declared", left, rel.Type, right, side.name)
+			return nil
+		}
+		if _, exists := side.table.ColumnByName(side.column); !exists {
+			w.warn("skipped the ref %s %s %s, column %s does not exist", left, rel.Type, right, side)
+			return nil
+		}
+	}
+	switch rel.Type {
+	case "<":
+		w.foreignKeys = append(w.foreignKeys, foreignKey{name: rel.Name, from: right, to: left})
+	case "<>":
+		return w.junction(left, right)
+	default:
+		// '>' and '-' hold the foreign key on the left side
+		w.foreignKeys = append(w.foreignKeys, foreignKey{name: rel.Name, from: left, to: right})
+	}
+	return nil
+}
+
+// junction returns the table 'left_right' referencing both sides
+func (w *sqlWriter) junction(left endpoint, right endpoint) *symbols.Table {
+	leftColumn, _ := left.table.ColumnByName(left.column)
+	rightColumn, _ := right.table.ColumnByName(right.column)
+	junction := &symbols.Table{
+		Scheme: left.scheme,
+		Name:   left.name + "_" + right.name,
+		Columns: []*symbols.Column{
+			{Name: left.name + "_" + left.column, Type: leftColumn.Type},
+			{Name: right.name + "_" + right.column, Type: rightColumn.Type},
+		},
+	}
+	junction.Indexes = []*symbols.Index{{
+		Columns:    []string{junction.Columns[0].Name, junction.Columns[1].Name},
+		PrimaryKey: true,
+	}}
+	for i, side := range []endpoint{left, right} {
+		from := endpoint{scheme: junction.Scheme, name: junction.Name, column: junction.Columns[i].Name}
+		w.foreignKeys = append(w.foreignKeys, foreignKey{from: from, to: side})
+	}
+	return junction
+}
+
+func (w *sqlWriter) foreignKey(key foreignKey) string {
+	constraint := ""
+	if key.name != "" {
+		constraint = "CONSTRAINT " + w.dialect.Quote(key.name) + " "
+	}
+	return fmt.Sprintf("%sFOREIGN KEY (%s) REFERENCES %s (%s)", constraint,
+		w.dialect.Quote(key.from.column), w.tableIdent(key.to.scheme, key.to.name), w.dialect.Quote(key.to.column))
+}
+
+// endpoint is a side of a ref, table is nil
+// if the table is not declared in the storage
+type endpoint struct {
+	scheme string
+	name   string
+	column string
+	table  *symbols.Table
+}
+
+// endpoint resolves aliases to the declared table
+func (w *sqlWriter) endpoint(scheme string, name string, column string) endpoint {
+	table, exists := w.storage.ResolveTable(scheme, name)
+	if !exists {
+		return endpoint{scheme: scheme, name: name, column: column}
+	}
+	return endpoint{scheme: table.Scheme, name: table.Name, column: column, table: table}
+}
+
+func (e endpoint) String() string {
+	return symbols.QualifiedName(e.scheme, e.name) + "." + e.column
+}
+
+func (w *sqlWriter) joinColumns(columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, w.dialect.Quote(column))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// joinIndexColumns quotes column names and
+// wraps expressions in parentheses
+func (w *sqlWriter) joinIndexColumns(columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if isExpression(column) {
+			quoted = append(quoted, "("+strings.Trim(column, "`")+")")
+			continue
+		}
+		quoted = append(quoted, w.dialect.Quote(column))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+//
+// helpers
+//
+
+// allRelationships returns the refs of all tables and refs
+// whose host table is not declared in storage
+func allRelationships(storage *symbols.Storage) []*symbols.Relationship {
+	relationships := make([]*symbols.Relationship, 0)
+	for _, table := range storage.Tables() {
+		relationships = append(relationships, table.References...)
+	}
+	return append(relationships, storage.Relationships()...)
+}
+
+// primaryKeyColumns returns the columns declared 'pk' or
+// the columns of a 'pk' index for composite primary keys
+func primaryKeyColumns(table *symbols.Table) []string {
+	for _, index := range table.Indexes {
+		if index.PrimaryKey {
+			return index.Columns
+		}
+	}
+	columns := make([]string, 0)
+	for _, column := range table.Columns {
+		if column.IsPrimaryKey() {
+			columns = append(columns, column.Name)
+		}
+	}
+	return columns
+}
+
+func enumValues(enum *symbols.Enum) string {
+	values := make([]string, 0, len(enum.Values))
+	for _, value := range enum.Values {
+		values = append(values, quoteString(value.Name))
+	}
+	return strings.Join(values, ", ")
+}
+
+func isExpression(column string) bool {
+	return strings.HasPrefix(column, "`")
+}
+
+func hasExpression(index *symbols.Index) bool {
+	for _, column := range index.Columns {
+		if isExpression(column) {
+			return true
+		}
+	}
+	return false
+}
+
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// indexName returns a name like orders_user_id_status_index
+func indexName(table *symbols.Table, index *symbols.Index) string {
+	parts := []string{table.Name}
+	for _, column := range index.Columns {
+		parts = append(parts, strings.Trim(nonWord.ReplaceAllString(column, "_"), "_"))
+	}
+	suffix := "_index"
+	if index.Unique {
+		suffix = "_unique"
+	}
+	return strings.Join(parts, "_") + suffix
+}
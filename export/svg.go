@@ -0,0 +1,295 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// measures of the svg diagram, text is monospace
+// so that its width follows from the length
+const (
+	svgFontSize     = 12
+	svgCharWidth    = 7.2
+	svgHeaderHeight = 28
+	svgRowHeight    = 22
+	svgPadding      = 8
+	// svgClusterPadding surrounds the tables of a cluster,
+	// the label is above them
+	svgClusterPadding = 16
+	svgClusterLabel   = 20
+	svgMargin         = 24
+)
+
+// SVG renders the selected tables as standalone svg image. Tables are
+// laid out in layers, referenced tables left of referencing ones, and
+// edges connect the rows of the columns with crow's foot markers.
+// Clusters are laid out first and placed as a whole.
+func SVG(storage *symbols.Storage, options DiagramOptions) (string, error) {
+	d, err := newDiagram(storage, options)
+	if err != nil {
+		return "", err
+	}
+	r := &svgRenderer{diagram: d, position: make(map[*symbols.Table]point)}
+	r.layout()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n",
+		svgNumber(r.width), svgNumber(r.height), svgNumber(r.width), svgNumber(r.height))
+	b.WriteString("  <style>\n")
+	fmt.Fprintf(&b, "    text { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: %dpx; dominant-baseline: central; }\n", svgFontSize)
+	b.WriteString("    .table { fill: #FFFFFF; stroke: #555555; }\n")
+	b.WriteString("    .row { stroke: #DDDDDD; }\n")
+	b.WriteString("    .type { fill: #777777; }\n")
+	b.WriteString("    .key { fill: #999999; font-style: italic; }\n")
+	b.WriteString("    .cluster { fill: #F7F7F7; stroke: #BBBBBB; stroke-dasharray: 4 2; }\n")
+	b.WriteString("    .edge { fill: none; stroke: #555555; }\n")
+	b.WriteString("    .marker { fill: #FFFFFF; stroke: #555555; }\n")
+	b.WriteString("  </style>\n")
+	fmt.Fprintf(&b, "  <rect width=\"100%%\" height=\"100%%\" fill=\"#FFFFFF\"/>\n")
+
+	for _, c := range r.clusters {
+		r.cluster(&b, c)
+	}
+	for _, e := range d.edges {
+		r.edge(&b, e)
+	}
+	for _, table := range d.tables {
+		r.table(&b, table)
+	}
+	b.WriteString("</svg>\n")
+	return b.String(), nil
+}
+
+type svgRenderer struct {
+	*diagram
+	position      map[*symbols.Table]point
+	clusters      []*svgCluster
+	width, height float64
+}
+
+// svgCluster is a laid out cluster
+type svgCluster struct {
+	*cluster
+	position      point
+	width, height float64
+}
+
+// layout places the tables of each cluster, then the clusters
+// and the remaining tables as boxes of one layout
+func (r *svgRenderer) layout() {
+	clusters, unclustered := r.diagram.clusters()
+	blocks := make([][]*symbols.Table, 0, len(clusters)+len(unclustered))
+	for _, c := range clusters {
+		blocks = append(blocks, c.tables)
+	}
+	for _, table := range unclustered {
+		blocks = append(blocks, []*symbols.Table{table})
+	}
+
+	blockOf := make(map[*symbols.Table]int)
+	for i, tables := range blocks {
+		for _, table := range tables {
+			blockOf[table] = i
+		}
+	}
+	// positions of the tables within their block
+	inner := make(map[*symbols.Table]point)
+	boxes := make([]box, len(blocks))
+	for i, tables := range blocks {
+		index := make(map[*symbols.Table]int)
+		tableBoxes := make([]box, len(tables))
+		for j, table := range tables {
+			index[table] = j
+			tableBoxes[j] = box{width: r.tableWidth(table), height: r.tableHeight(table)}
+		}
+		edges := make([][2]int, 0)
+		for _, e := range r.edges {
+			from, fromInside := index[e.from]
+			to, toInside := index[e.to]
+			if fromInside && toInside {
+				edges = append(edges, [2]int{from, to})
+			}
+		}
+		points, width, height := layered(tableBoxes, edges)
+		for j, table := range tables {
+			inner[table] = points[j]
+		}
+		boxes[i] = box{width: width, height: height}
+		if i < len(clusters) {
+			boxes[i].width += 2 * svgClusterPadding
+			boxes[i].height += 2*svgClusterPadding + svgClusterLabel
+		}
+	}
+
+	edges := make([][2]int, 0)
+	for _, e := range r.edges {
+		if from, to := blockOf[e.from], blockOf[e.to]; from != to {
+			edges = append(edges, [2]int{from, to})
+		}
+	}
+	points, width, height := layered(boxes, edges)
+	r.width, r.height = width+2*svgMargin, height+2*svgMargin
+
+	for i, tables := range blocks {
+		offset := point{x: points[i].x + svgMargin, y: points[i].y + svgMargin}
+		if i < len(clusters) {
+			r.clusters = append(r.clusters, &svgCluster{
+				cluster:  clusters[i],
+				position: offset,
+				width:    boxes[i].width,
+				height:   boxes[i].height,
+			})
+			offset.x += svgClusterPadding
+			offset.y += svgClusterPadding + svgClusterLabel
+		}
+		for _, table := range tables {
+			r.position[table] = point{x: offset.x + inner[table].x, y: offset.y + inner[table].y}
+		}
+	}
+}
+
+func (r *svgRenderer) tableWidth(table *symbols.Table) float64 {
+	width := textWidth(diagramLabel(table)) + 2*svgPadding
+	for _, column := range table.Columns {
+		row := textWidth(column.Name) + textWidth(column.Type) + 3*svgPadding
+		if keys := r.columnKeys(table, column); len(keys) > 0 {
+			row += textWidth(strings.Join(keys, ",")) + svgPadding
+		}
+		width = math.Max(width, row)
+	}
+	return math.Ceil(math.Max(width, 120))
+}
+
+func (r *svgRenderer) tableHeight(table *symbols.Table) float64 {
+	return svgHeaderHeight + float64(len(table.Columns))*svgRowHeight
+}
+
+func textWidth(text string) float64 {
+	return float64(len([]rune(text))) * svgCharWidth
+}
+
+func (r *svgRenderer) cluster(b *strings.Builder, c *svgCluster) {
+	style := ""
+	if c.color != "" && r.options.HeaderColors {
+		style = fmt.Sprintf(" style=\"stroke: %s\"", html.EscapeString(c.color))
+	}
+	fmt.Fprintf(b, "  <g>\n    <rect class=\"cluster\" x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" rx=\"6\"%s/>\n",
+		svgNumber(c.position.x), svgNumber(c.position.y), svgNumber(c.width), svgNumber(c.height), style)
+	fmt.Fprintf(b, "    <text x=\"%s\" y=\"%s\" font-weight=\"bold\">%s</text>\n  </g>\n",
+		svgNumber(c.position.x+svgClusterPadding), svgNumber(c.position.y+svgClusterPadding), html.EscapeString(c.name))
+}
+
+func (r *svgRenderer) table(b *strings.Builder, table *symbols.Table) {
+	p := r.position[table]
+	width, height := r.tableWidth(table), r.tableHeight(table)
+	header, text := defaultHeaderColor, "#000000"
+	if color := r.headerColor(table); color != "" {
+		header, text = color, contrastColor(color)
+	}
+
+	fmt.Fprintf(b, "  <g>\n")
+	if table.Note != "" {
+		fmt.Fprintf(b, "    <title>%s</title>\n", html.EscapeString(table.Note))
+	}
+	fmt.Fprintf(b, "    <rect class=\"table\" x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" rx=\"4\"/>\n",
+		svgNumber(p.x), svgNumber(p.y), svgNumber(width), svgNumber(height))
+	fmt.Fprintf(b, "    <path d=\"M%s,%s h%s v%s h%s z\" fill=\"%s\" stroke=\"#555555\"/>\n",
+		svgNumber(p.x), svgNumber(p.y), svgNumber(width), svgNumber(svgHeaderHeight), svgNumber(-width), html.EscapeString(header))
+	fmt.Fprintf(b, "    <text x=\"%s\" y=\"%s\" font-weight=\"bold\" fill=\"%s\">%s</text>\n",
+		svgNumber(p.x+svgPadding), svgNumber(p.y+svgHeaderHeight/2), html.EscapeString(text), html.EscapeString(diagramLabel(table)))
+
+	for i, column := range table.Columns {
+		top := p.y + svgHeaderHeight + float64(i)*svgRowHeight
+		middle := top + svgRowHeight/2
+		if i > 0 {
+			fmt.Fprintf(b, "    <line class=\"row\" x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\"/>\n",
+				svgNumber(p.x+1), svgNumber(top), svgNumber(p.x+width-1), svgNumber(top))
+		}
+		fmt.Fprintf(b, "    <g>")
+		if note, exists := column.Setting("note"); exists {
+			fmt.Fprintf(b, "<title>%s</title>", html.EscapeString(note))
+		}
+		fmt.Fprintf(b, "<text x=\"%s\" y=\"%s\">%s", svgNumber(p.x+svgPadding), svgNumber(middle), html.EscapeString(column.Name))
+		if keys := r.columnKeys(table, column); len(keys) > 0 {
+			fmt.Fprintf(b, " <tspan class=\"key\">%s</tspan>", strings.Join(keys, ","))
+		}
+		fmt.Fprintf(b, "</text><text class=\"type\" x=\"%s\" y=\"%s\" text-anchor=\"end\">%s</text></g>\n",
+			svgNumber(p.x+width-svgPadding), svgNumber(middle), html.EscapeString(column.Type))
+	}
+	b.WriteString("  </g>\n")
+}
+
+// anchor returns the point where an edge leaves the row of column,
+// on the right side if side is 1 and on the left side if it is -1
+func (r *svgRenderer) anchor(table *symbols.Table, column string, side float64) point {
+	p := r.position[table]
+	y := p.y + svgHeaderHeight/2
+	for i, candidate := range table.Columns {
+		if candidate.Name == column {
+			y = p.y + svgHeaderHeight + float64(i)*svgRowHeight + svgRowHeight/2
+		}
+	}
+	if side > 0 {
+		return point{x: p.x + r.tableWidth(table), y: y}
+	}
+	return point{x: p.x, y: y}
+}
+
+// edge draws e as curve between the sides of the tables facing
+// each other, both on the right side if the tables overlap
+func (r *svgRenderer) edge(b *strings.Builder, e *edge) {
+	from, to := r.position[e.from], r.position[e.to]
+	fromSide, toSide := 1.0, 1.0
+	switch {
+	case from.x+r.tableWidth(e.from) < to.x:
+		toSide = -1
+	case to.x+r.tableWidth(e.to) < from.x:
+		fromSide = -1
+	}
+	start := r.anchor(e.from, e.fromColumn, fromSide)
+	end := r.anchor(e.to, e.toColumn, toSide)
+	bend := math.Max(40, math.Abs(end.x-start.x)/2)
+	fmt.Fprintf(b, "  <path class=\"edge\" d=\"M%s,%s C%s,%s %s,%s %s,%s\"/>\n",
+		svgNumber(start.x), svgNumber(start.y),
+		svgNumber(start.x+fromSide*bend), svgNumber(start.y),
+		svgNumber(end.x+toSide*bend), svgNumber(end.y),
+		svgNumber(end.x), svgNumber(end.y))
+
+	tail, head := dotArrows(e)
+	svgMarker(b, tail, start, fromSide)
+	svgMarker(b, head, end, toSide)
+}
+
+// svgMarker draws a crow's foot marker like the graphviz arrow
+// shapes crowodot, teetee and teeodot at p, pointing into direction
+func svgMarker(b *strings.Builder, shape string, p point, direction float64) {
+	line := func(x1, y1, x2, y2 float64) {
+		fmt.Fprintf(b, "  <line class=\"edge\" x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\"/>\n",
+			svgNumber(p.x+direction*x1), svgNumber(p.y+y1), svgNumber(p.x+direction*x2), svgNumber(p.y+y2))
+	}
+	circle := func(x float64) {
+		fmt.Fprintf(b, "  <circle class=\"marker\" cx=\"%s\" cy=\"%s\" r=\"4\"/>\n", svgNumber(p.x+direction*x), svgNumber(p.y))
+	}
+	switch shape {
+	case "crowodot":
+		line(12, 0, 0, -6)
+		line(12, 0, 0, 6)
+		circle(17)
+	case "teetee":
+		line(8, -6, 8, 6)
+		line(12, -6, 12, 6)
+	case "teeodot":
+		line(8, -6, 8, 6)
+		circle(16)
+	}
+}
+
+// svgNumber formats coordinates with at most one decimal
+func svgNumber(value float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0")
+}
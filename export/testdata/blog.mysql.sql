@@ -0,0 +1,27 @@
+CREATE TABLE `authors` (
+  `id` int AUTO_INCREMENT PRIMARY KEY,
+  `handle` VARCHAR(40) NOT NULL UNIQUE,
+  `active` boolean DEFAULT true,
+  `joined_at` timestamp DEFAULT (now())
+) COMMENT='People writing posts';
+
+CREATE TABLE `posts` (
+  `id` int AUTO_INCREMENT PRIMARY KEY,
+  `author_id` int NOT NULL,
+  `title` VARCHAR(255) NOT NULL,
+  `state` ENUM('draft', 'published') DEFAULT 'draft',
+  `body` text
+);
+
+CREATE TABLE `profiles` (
+  `author_id` int PRIMARY KEY,
+  `bio` text COMMENT 'shown on the author page'
+);
+
+CREATE INDEX `posts_by_author` ON `posts` (`author_id`);
+
+CREATE UNIQUE INDEX `posts_author_id_title_unique` ON `posts` (`author_id`, `title`);
+
+ALTER TABLE `posts` ADD CONSTRAINT `posts_author` FOREIGN KEY (`author_id`) REFERENCES `authors` (`id`);
+
+ALTER TABLE `profiles` ADD FOREIGN KEY (`author_id`) REFERENCES `authors` (`id`);
@@ -0,0 +1,33 @@
+CREATE TYPE "post_state" AS ENUM ('draft', 'published');
+
+CREATE TABLE "authors" (
+  "id" int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "handle" varchar(40) NOT NULL UNIQUE,
+  "active" boolean DEFAULT true,
+  "joined_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "posts" (
+  "id" int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "author_id" int NOT NULL,
+  "title" varchar NOT NULL,
+  "state" "post_state" DEFAULT 'draft',
+  "body" text
+);
+
+CREATE TABLE "profiles" (
+  "author_id" int PRIMARY KEY,
+  "bio" text
+);
+
+CREATE INDEX "posts_by_author" ON "posts" ("author_id");
+
+CREATE UNIQUE INDEX ON "posts" ("author_id", "title");
+
+COMMENT ON TABLE "authors" IS 'People writing posts';
+
+COMMENT ON COLUMN "profiles"."bio" IS 'shown on the author page';
+
+ALTER TABLE "posts" ADD CONSTRAINT "posts_author" FOREIGN KEY ("author_id") REFERENCES "authors" ("id");
+
+ALTER TABLE "profiles" ADD FOREIGN KEY ("author_id") REFERENCES "authors" ("id");
@@ -0,0 +1,26 @@
+CREATE TABLE "authors" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "handle" varchar(40) NOT NULL UNIQUE,
+  "active" boolean DEFAULT 1,
+  "joined_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "posts" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "author_id" INTEGER NOT NULL,
+  "title" varchar NOT NULL,
+  "state" TEXT DEFAULT 'draft',
+  "body" text,
+  CHECK ("state" IN ('draft', 'published')),
+  CONSTRAINT "posts_author" FOREIGN KEY ("author_id") REFERENCES "authors" ("id")
+);
+
+CREATE TABLE "profiles" (
+  "author_id" INTEGER PRIMARY KEY,
+  "bio" text,
+  FOREIGN KEY ("author_id") REFERENCES "authors" ("id")
+);
+
+CREATE INDEX "posts_by_author" ON "posts" ("author_id");
+
+CREATE UNIQUE INDEX "posts_author_id_title_unique" ON "posts" ("author_id", "title");
@@ -0,0 +1,32 @@
+CREATE TABLE [authors] (
+  [id] int IDENTITY(1,1) PRIMARY KEY,
+  [handle] NVARCHAR(40) NOT NULL UNIQUE,
+  [active] BIT DEFAULT 1,
+  [joined_at] DATETIME2 DEFAULT (now())
+);
+
+CREATE TABLE [posts] (
+  [id] int IDENTITY(1,1) PRIMARY KEY,
+  [author_id] int NOT NULL,
+  [title] NVARCHAR(255) NOT NULL,
+  [state] NVARCHAR(255) DEFAULT 'draft',
+  [body] NVARCHAR(MAX),
+  CHECK ([state] IN ('draft', 'published'))
+);
+
+CREATE TABLE [profiles] (
+  [author_id] int PRIMARY KEY,
+  [bio] NVARCHAR(MAX)
+);
+
+CREATE INDEX [posts_by_author] ON [posts] ([author_id]);
+
+CREATE UNIQUE INDEX [posts_author_id_title_unique] ON [posts] ([author_id], [title]);
+
+EXEC sp_addextendedproperty 'MS_Description', 'People writing posts', 'SCHEMA', 'dbo', 'TABLE', 'authors';
+
+EXEC sp_addextendedproperty 'MS_Description', 'shown on the author page', 'SCHEMA', 'dbo', 'TABLE', 'profiles', 'COLUMN', 'bio';
+
+ALTER TABLE [posts] ADD CONSTRAINT [posts_author] FOREIGN KEY ([author_id]) REFERENCES [authors] ([id]);
+
+ALTER TABLE [profiles] ADD FOREIGN KEY ([author_id]) REFERENCES [authors] ([id]);
@@ -0,0 +1,11 @@
+CREATE TABLE `events` (
+  `id` bigint AUTO_INCREMENT PRIMARY KEY,
+  `seq` integer AUTO_INCREMENT,
+  `name` VARCHAR(255)
+);
+
+CREATE TABLE `event_tags` (
+  `event_id` bigint AUTO_INCREMENT,
+  `tag` VARCHAR(32),
+  PRIMARY KEY (`event_id`, `tag`)
+);
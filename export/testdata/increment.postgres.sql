@@ -0,0 +1,11 @@
+CREATE TABLE "events" (
+  "id" bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "seq" integer GENERATED BY DEFAULT AS IDENTITY,
+  "name" varchar
+);
+
+CREATE TABLE "event_tags" (
+  "event_id" bigint GENERATED BY DEFAULT AS IDENTITY,
+  "tag" varchar(32),
+  PRIMARY KEY ("event_id", "tag")
+);
@@ -0,0 +1,15 @@
+-- WARNING: SQLite only increments a single column primary key, events.seq is not incremented
+
+CREATE TABLE "events" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "seq" INTEGER,
+  "name" varchar
+);
+
+-- WARNING: SQLite only increments a single column primary key, event_tags.event_id is not incremented
+
+CREATE TABLE "event_tags" (
+  "event_id" bigint,
+  "tag" varchar(32),
+  PRIMARY KEY ("event_id", "tag")
+);
@@ -0,0 +1,11 @@
+CREATE TABLE [events] (
+  [id] bigint IDENTITY(1,1) PRIMARY KEY,
+  [seq] integer IDENTITY(1,1),
+  [name] NVARCHAR(255)
+);
+
+CREATE TABLE [event_tags] (
+  [event_id] bigint IDENTITY(1,1),
+  [tag] NVARCHAR(32),
+  PRIMARY KEY ([event_id], [tag])
+);
@@ -0,0 +1,5 @@
+CREATE TABLE `rules` (
+  `id` integer PRIMARY KEY,
+  `threshold` integer DEFAULT 10 COMMENT 'must be > 0 & < 100',
+  `expression` text COMMENT 'x | y <script>alert(1)</script>'
+) COMMENT='Rules for <em>alerts</em> & reports';
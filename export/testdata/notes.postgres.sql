@@ -0,0 +1,13 @@
+CREATE TYPE "level" AS ENUM ('low', 'high');
+
+CREATE TABLE "rules" (
+  "id" integer PRIMARY KEY,
+  "threshold" integer DEFAULT 10,
+  "expression" text
+);
+
+COMMENT ON TABLE "rules" IS 'Rules for <em>alerts</em> & reports';
+
+COMMENT ON COLUMN "rules"."threshold" IS 'must be > 0 & < 100';
+
+COMMENT ON COLUMN "rules"."expression" IS 'x | y <script>alert(1)</script>';
@@ -0,0 +1,5 @@
+CREATE TABLE "rules" (
+  "id" INTEGER PRIMARY KEY,
+  "threshold" INTEGER DEFAULT 10,
+  "expression" text
+);
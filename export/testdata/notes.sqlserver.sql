@@ -0,0 +1,11 @@
+CREATE TABLE [rules] (
+  [id] integer PRIMARY KEY,
+  [threshold] integer DEFAULT 10,
+  [expression] NVARCHAR(MAX)
+);
+
+EXEC sp_addextendedproperty 'MS_Description', 'Rules for <em>alerts</em> & reports', 'SCHEMA', 'dbo', 'TABLE', 'rules';
+
+EXEC sp_addextendedproperty 'MS_Description', 'must be > 0 & < 100', 'SCHEMA', 'dbo', 'TABLE', 'rules', 'COLUMN', 'threshold';
+
+EXEC sp_addextendedproperty 'MS_Description', 'x | y <script>alert(1)</script>', 'SCHEMA', 'dbo', 'TABLE', 'rules', 'COLUMN', 'expression';
@@ -0,0 +1,4 @@
+CREATE TABLE `settings` (
+  `name` VARCHAR(255) PRIMARY KEY,
+  `value` text
+);
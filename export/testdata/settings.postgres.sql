@@ -0,0 +1,4 @@
+CREATE TABLE "settings" (
+  "name" varchar PRIMARY KEY,
+  "value" text
+);
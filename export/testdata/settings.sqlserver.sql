@@ -0,0 +1,4 @@
+CREATE TABLE [settings] (
+  [name] NVARCHAR(255) PRIMARY KEY,
+  [value] NVARCHAR(MAX)
+);
@@ -0,0 +1,45 @@
+CREATE SCHEMA IF NOT EXISTS `core`;
+
+CREATE TABLE `core`.`users` (
+  `id` int AUTO_INCREMENT PRIMARY KEY,
+  `email` VARCHAR(255) NOT NULL UNIQUE COMMENT 'login',
+  `name` VARCHAR(255) DEFAULT 'O''Brien'
+) COMMENT='Registered users';
+
+CREATE TABLE `orders` (
+  `id` int AUTO_INCREMENT PRIMARY KEY,
+  `user_id` int,
+  `status` ENUM('created', 'shipped', 'in transit') DEFAULT 'created',
+  `total` decimal(10,2) DEFAULT 0,
+  `created_at` timestamp DEFAULT (now())
+);
+
+CREATE TABLE `tags` (
+  `id` int PRIMARY KEY
+);
+
+CREATE TABLE `order_lines` (
+  `order_id` int,
+  `line` int,
+  PRIMARY KEY (`order_id`, `line`)
+);
+
+CREATE TABLE `orders_tags` (
+  `orders_id` int,
+  `tags_id` int,
+  PRIMARY KEY (`orders_id`, `tags_id`)
+);
+
+CREATE INDEX `orders_user_id_index` ON `orders` (`user_id`);
+
+CREATE UNIQUE INDEX `orders_user_status` ON `orders` (`user_id`, `status`);
+
+CREATE INDEX `orders_lower_status_index` ON `orders` ((lower(status))) USING HASH;
+
+ALTER TABLE `orders` ADD FOREIGN KEY (`user_id`) REFERENCES `core`.`users` (`id`);
+
+ALTER TABLE `orders_tags` ADD FOREIGN KEY (`orders_id`) REFERENCES `orders` (`id`);
+
+ALTER TABLE `orders_tags` ADD FOREIGN KEY (`tags_id`) REFERENCES `tags` (`id`);
+
+ALTER TABLE `order_lines` ADD FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`);
@@ -0,0 +1,51 @@
+CREATE SCHEMA IF NOT EXISTS "core";
+
+CREATE TYPE "core"."order_status" AS ENUM ('created', 'shipped', 'in transit');
+
+CREATE TABLE "core"."users" (
+  "id" int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "email" varchar(255) NOT NULL UNIQUE,
+  "name" varchar DEFAULT 'O''Brien'
+);
+
+CREATE TABLE "orders" (
+  "id" int GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" int,
+  "status" "core"."order_status" DEFAULT 'created',
+  "total" decimal(10,2) DEFAULT 0,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "tags" (
+  "id" int PRIMARY KEY
+);
+
+CREATE TABLE "order_lines" (
+  "order_id" int,
+  "line" int,
+  PRIMARY KEY ("order_id", "line")
+);
+
+CREATE TABLE "orders_tags" (
+  "orders_id" int,
+  "tags_id" int,
+  PRIMARY KEY ("orders_id", "tags_id")
+);
+
+CREATE INDEX ON "orders" ("user_id");
+
+CREATE UNIQUE INDEX "orders_user_status" ON "orders" ("user_id", "status");
+
+CREATE INDEX ON "orders" USING HASH ((lower(status)));
+
+COMMENT ON TABLE "core"."users" IS 'Registered users';
+
+COMMENT ON COLUMN "core"."users"."email" IS 'login';
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("user_id") REFERENCES "core"."users" ("id");
+
+ALTER TABLE "orders_tags" ADD FOREIGN KEY ("orders_id") REFERENCES "orders" ("id");
+
+ALTER TABLE "orders_tags" ADD FOREIGN KEY ("tags_id") REFERENCES "tags" ("id");
+
+ALTER TABLE "order_lines" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id");
@@ -0,0 +1,40 @@
+CREATE TABLE "users" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "email" varchar(255) NOT NULL UNIQUE,
+  "name" varchar DEFAULT 'O''Brien'
+);
+
+CREATE TABLE "orders" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "user_id" INTEGER,
+  "status" TEXT DEFAULT 'created',
+  "total" decimal(10,2) DEFAULT 0,
+  "created_at" timestamp DEFAULT (now()),
+  CHECK ("status" IN ('created', 'shipped', 'in transit')),
+  FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);
+
+CREATE TABLE "tags" (
+  "id" INTEGER PRIMARY KEY
+);
+
+CREATE TABLE "order_lines" (
+  "order_id" INTEGER,
+  "line" INTEGER,
+  PRIMARY KEY ("order_id", "line"),
+  FOREIGN KEY ("order_id") REFERENCES "orders" ("id")
+);
+
+CREATE TABLE "orders_tags" (
+  "orders_id" INTEGER,
+  "tags_id" INTEGER,
+  PRIMARY KEY ("orders_id", "tags_id"),
+  FOREIGN KEY ("orders_id") REFERENCES "orders" ("id"),
+  FOREIGN KEY ("tags_id") REFERENCES "tags" ("id")
+);
+
+CREATE INDEX "orders_user_id_index" ON "orders" ("user_id");
+
+CREATE UNIQUE INDEX "orders_user_status" ON "orders" ("user_id", "status");
+
+CREATE INDEX "orders_lower_status_index" ON "orders" ((lower(status)));
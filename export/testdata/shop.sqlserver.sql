@@ -0,0 +1,50 @@
+IF SCHEMA_ID('core') IS NULL EXEC('CREATE SCHEMA [core]');
+
+CREATE TABLE [core].[users] (
+  [id] int IDENTITY(1,1) PRIMARY KEY,
+  [email] NVARCHAR(255) NOT NULL UNIQUE,
+  [name] NVARCHAR(255) DEFAULT 'O''Brien'
+);
+
+CREATE TABLE [orders] (
+  [id] int IDENTITY(1,1) PRIMARY KEY,
+  [user_id] int,
+  [status] NVARCHAR(255) DEFAULT 'created',
+  [total] decimal(10,2) DEFAULT 0,
+  [created_at] DATETIME2 DEFAULT (now()),
+  CHECK ([status] IN ('created', 'shipped', 'in transit'))
+);
+
+CREATE TABLE [tags] (
+  [id] int PRIMARY KEY
+);
+
+CREATE TABLE [order_lines] (
+  [order_id] int,
+  [line] int,
+  PRIMARY KEY ([order_id], [line])
+);
+
+CREATE TABLE [orders_tags] (
+  [orders_id] int,
+  [tags_id] int,
+  PRIMARY KEY ([orders_id], [tags_id])
+);
+
+CREATE INDEX [orders_user_id_index] ON [orders] ([user_id]);
+
+CREATE UNIQUE INDEX [orders_user_status] ON [orders] ([user_id], [status]);
+
+-- SQL Server does not support expression indexes: orders (`lower(status)`)
+
+EXEC sp_addextendedproperty 'MS_Description', 'Registered users', 'SCHEMA', 'core', 'TABLE', 'users';
+
+EXEC sp_addextendedproperty 'MS_Description', 'login', 'SCHEMA', 'core', 'TABLE', 'users', 'COLUMN', 'email';
+
+ALTER TABLE [orders] ADD FOREIGN KEY ([user_id]) REFERENCES [core].[users] ([id]);
+
+ALTER TABLE [orders_tags] ADD FOREIGN KEY ([orders_id]) REFERENCES [orders] ([id]);
+
+ALTER TABLE [orders_tags] ADD FOREIGN KEY ([tags_id]) REFERENCES [tags] ([id]);
+
+ALTER TABLE [order_lines] ADD FOREIGN KEY ([order_id]) REFERENCES [orders] ([id]);
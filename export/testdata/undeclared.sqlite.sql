@@ -0,0 +1,26 @@
+-- WARNING: skipped the ref public.users.team_id > public.teams.id, table teams is not declared
+
+-- WARNING: skipped the ref public.users.id < public.audits.user_id, table audits is not declared
+
+-- WARNING: skipped the ref public.users.id - public.profiles.user_id, table profiles is not declared
+
+-- WARNING: skipped the ref public.users.id <> public.groups.id, table groups is not declared
+
+-- WARNING: skipped the ref public.users.missing > public.tags.id, column public.users.missing does not exist
+
+CREATE TABLE "users" (
+  "id" INTEGER PRIMARY KEY,
+  "team_id" INTEGER
+);
+
+CREATE TABLE "tags" (
+  "id" INTEGER PRIMARY KEY
+);
+
+CREATE TABLE "users_tags" (
+  "users_id" INTEGER,
+  "tags_id" INTEGER,
+  PRIMARY KEY ("users_id", "tags_id"),
+  FOREIGN KEY ("users_id") REFERENCES "users" ("id"),
+  FOREIGN KEY ("tags_id") REFERENCES "tags" ("id")
+);
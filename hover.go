@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// textDocumentHover shows sticky notes, tables and
+// columns at the cursor along with their notes.
+func textDocumentHover(context *glsp.Context, params *protocol.HoverParams) (*protocol.Hover, error) {
+	document, exists := getDocument(params.TextDocument.URI)
+	if !exists {
+		return nil, nil
+	}
+	state := document.State()
+	storage := state.Snapshot.Storage
+
+	line := analysis.Line(state.Text, params.Position.Line)
+	character := byteIndex(line, params.Position.Character, positionEncoding)
+	segments, index := dottedNameAt(line, utf8.RuneCountInString(line[:character]))
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	var content string
+	scope := document.Scope()
+	if index > 0 && index == len(segments)-1 {
+		if symbol, exists := scope.Lookup(segments[index-1]); exists {
+			if column, exists := symbol.Table.ColumnByName(segments[index]); exists {
+				content = hoverColumn(symbol.Table, column)
+			}
+		}
+	}
+	if content == "" {
+		column := newLineMapper(state.Text).fromClient(params.Position.Line, params.Position.Character)
+		if note, exists := storage.NoteByName(segments[index]); exists {
+			content = hoverNote(note)
+		} else if table, column, exists := storage.ColumnAt(params.Position.Line, column); exists && column.Name == segments[index] {
+			content = hoverColumn(table, column)
+		} else if symbol, exists := scope.Lookup(segments[index]); exists {
+			content = hoverTable(symbol.Table)
+		}
+	}
+	if content == "" {
+		return nil, nil
+	}
+
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.MarkupKindMarkdown,
+			Value: content,
+		},
+	}, nil
+}
+
+func hoverNote(note *symbols.Note) string {
+	return fmt.Sprintf("**Note** `%s`\n\n%s", note.Name, note.Content)
+}
+
+func hoverTable(table *symbols.Table) string {
+	content := fmt.Sprintf("**Table** `%s`", table.QualifiedName())
+	if table.Alias != "" {
+		content += fmt.Sprintf(" as `%s`", table.Alias)
+	}
+	if table.Note != "" {
+		content += "\n\n" + table.Note
+	}
+	return content
+}
+
+func hoverColumn(table *symbols.Table, column *symbols.Column) string {
+	var settings []string
+	var note string
+	for _, constraint := range column.Constraints {
+		switch constraint.Key {
+		case "note":
+			note = constraint.Value
+		case "":
+			settings = append(settings, constraint.Value)
+		default:
+			settings = append(settings, constraint.Key+": "+constraint.Value)
+		}
+	}
+
+	content := fmt.Sprintf("**Column** `%s.%s` `%s`", table.Name, column.Name, column.Type)
+	if len(settings) > 0 {
+		content += " [" + strings.Join(settings, ", ") + "]"
+	}
+	if note != "" {
+		content += "\n\n" + note
+	}
+	return content
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/h0rzn/dbml-lsp/export"
+	"github.com/h0rzn/dbml-lsp/importer"
+)
+
+// runImport implements 'dbml-lsp import <file.sql>' and writes
+// the dbml to stdout, warnings about skipped definitions to stderr
+func runImport(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dbml-lsp import <file.sql>")
+	}
+	text, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	storage, warnings, err := importer.SQL(string(text))
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", args[0], warning)
+	}
+	if err != nil {
+		return fmt.Errorf("%s:%s", args[0], err.Error())
+	}
+	output, err := export.DBML(storage)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Print(output)
+	return err
+}
+
+// runIntrospect implements 'dbml-lsp introspect <file.sqlite>'
+// and writes the schema of the database as dbml to stdout
+func runIntrospect(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dbml-lsp introspect <file.sqlite>")
+	}
+	storage, warnings, err := importer.SQLite(args[0])
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", args[0], warning)
+	}
+	if err != nil {
+		return err
+	}
+	output, err := export.DBML(storage)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Print(output)
+	return err
+}
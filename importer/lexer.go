@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenWord is a keyword or an unquoted identifier
+	tokenWord
+	// tokenIdent is a quoted identifier, "name" or `name`
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+// token is a lexeme of sql source, value holds the unquoted
+// text of identifiers and strings, start and end the offsets
+// of the lexeme in the source
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+	start int
+	end   int
+}
+
+// is reports whether t is one of the keywords, ignoring case
+func (t token) is(words ...string) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(t.value, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPunct reports whether t is the punctuation p
+func (t token) isPunct(p string) bool {
+	return t.kind == tokenPunct && t.value == p
+}
+
+// isName reports whether t can be an identifier
+func (t token) isName() bool {
+	return t.kind == tokenWord || t.kind == tokenIdent
+}
+
+// lex splits sql into tokens, comments and whitespace are dropped.
+// The last token is always tokenEOF.
+func lex(sql string) []token {
+	src := []rune(sql)
+	tokens := make([]token, 0, len(src)/4)
+	line := 1
+	i := 0
+	emit := func(kind tokenKind, value string, start int) {
+		tokens = append(tokens, token{kind: kind, value: value, line: line, start: start, end: i})
+	}
+	for i < len(src) {
+		r := src[i]
+		start := i
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && at(src, i+1) == '-', r == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case r == '/' && at(src, i+1) == '*':
+			i += 2
+			for i < len(src) && !(src[i] == '*' && at(src, i+1) == '/') {
+				if src[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+		case r == '\'':
+			value, lines := quoted(src, &i, '\'', true)
+			emit(tokenString, value, start)
+			line += lines
+		case r == '"', r == '`':
+			value, lines := quoted(src, &i, r, false)
+			emit(tokenIdent, value, start)
+			line += lines
+		case r == '$' && dollarTag(src, i) != "":
+			tag := dollarTag(src, i)
+			i += len([]rune(tag))
+			end := strings.Index(string(src[i:]), tag)
+			if end < 0 {
+				end = len(string(src[i:]))
+			}
+			body := []rune(string(src[i:])[:end])
+			line += strings.Count(string(body), "\n")
+			i += len(body) + len([]rune(tag))
+			emit(tokenString, string(body), start)
+		case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(at(src, i+1))):
+			for i < len(src) && (unicode.IsDigit(src[i]) || src[i] == '.' ||
+				((src[i] == 'e' || src[i] == 'E') && unicode.IsDigit(at(src, i+1)))) {
+				i++
+			}
+			emit(tokenNumber, string(src[start:i]), start)
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(src) && (unicode.IsLetter(src[i]) || unicode.IsDigit(src[i]) || src[i] == '_' || src[i] == '$') {
+				i++
+			}
+			emit(tokenWord, string(src[start:i]), start)
+		case r == ':' && at(src, i+1) == ':':
+			i += 2
+			emit(tokenPunct, "::", start)
+		default:
+			i++
+			emit(tokenPunct, string(r), start)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, line: line, start: len(src), end: len(src)})
+	return tokens
+}
+
+// at returns the rune at i or 0 after the end of src
+func at(src []rune, i int) rune {
+	if i < len(src) {
+		return src[i]
+	}
+	return 0
+}
+
+// quoted scans the text enclosed in quote starting at *i, a doubled quote
+// escapes the quote. Strings also accept backslash escapes like \'.
+// Returns the unquoted text and the number of line breaks.
+func quoted(src []rune, i *int, quote rune, backslash bool) (string, int) {
+	var b strings.Builder
+	lines := 0
+	*i++
+	for *i < len(src) {
+		r := src[*i]
+		switch {
+		case backslash && r == '\\' && *i+1 < len(src):
+			b.WriteRune(unescape(src[*i+1]))
+			*i += 2
+			continue
+		case r == quote && at(src, *i+1) == quote:
+			b.WriteRune(quote)
+			*i += 2
+			continue
+		case r == quote:
+			*i++
+			return b.String(), lines
+		case r == '\n':
+			lines++
+		}
+		b.WriteRune(r)
+		*i++
+	}
+	return b.String(), lines
+}
+
+func unescape(r rune) rune {
+	switch r {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case '0':
+		return 0
+	}
+	return r
+}
+
+// dollarTag returns the opening tag of a dollar quoted
+// string like $$ or $body$, empty if there is none
+func dollarTag(src []rune, i int) string {
+	j := i + 1
+	for j < len(src) && (unicode.IsLetter(src[j]) || unicode.IsDigit(src[j]) || src[j] == '_') {
+		j++
+	}
+	if j < len(src) && src[j] == '$' {
+		return string(src[i : j+1])
+	}
+	return ""
+}
@@ -0,0 +1,1009 @@
+// Package importer builds symbols from the schema
+// definitions of other formats, e.g. SQL dumps.
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// SQL reads the CREATE TABLE, CREATE TYPE ... AS ENUM, CREATE INDEX,
+// ALTER TABLE and COMMENT ON statements of a PostgreSQL or MySQL
+// schema dump. Other statements are skipped. The warnings name
+// the definitions that have no dbml equivalent and the skipped
+// statements that define views, routines or rows.
+func SQL(sql string) (*symbols.Storage, []string, error) {
+	p := &sqlParser{
+		src:     []rune(sql),
+		tokens:  lex(sql),
+		storage: symbols.NewStorage(),
+	}
+	for !p.peek().kindOf(tokenEOF) {
+		if err := p.statement(); err != nil {
+			return p.storage, p.warnings, fmt.Errorf("line %d: %s", p.peek().line, err.Error())
+		}
+	}
+	p.resolveRefs()
+	return p.storage, p.warnings, nil
+}
+
+type sqlParser struct {
+	src      []rune
+	tokens   []token
+	pos      int
+	storage  *symbols.Storage
+	refs     []*foreignKey
+	warnings []string
+	// skippedData holds the tables with skipped INSERT statements
+	skippedData map[tableName]bool
+}
+
+// foreignKey is a reference collected until all tables are
+// declared, columns are empty for references to the primary key
+type foreignKey struct {
+	name   string
+	inline bool
+	from   tableName
+	column string
+	to     tableName
+	target string
+	line   int
+}
+
+type tableName struct {
+	scheme string
+	name   string
+}
+
+func (t token) kindOf(kind tokenKind) bool {
+	return t.kind == kind
+}
+
+func (p *sqlParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *sqlParser) peekAt(offset int) token {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *sqlParser) next() token {
+	item := p.tokens[p.pos]
+	if item.kind != tokenEOF {
+		p.pos++
+	}
+	return item
+}
+
+// accept consumes the keyword sequence words if it follows
+func (p *sqlParser) accept(words ...string) bool {
+	for i, word := range words {
+		if !p.peekAt(i).is(word) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *sqlParser) acceptPunct(punct string) bool {
+	if p.peek().isPunct(punct) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectPunct(punct string) error {
+	if !p.acceptPunct(punct) {
+		return fmt.Errorf("found %q, expected %q", p.peek().value, punct)
+	}
+	return nil
+}
+
+func (p *sqlParser) warn(line int, format string, args ...any) {
+	p.warnings = append(p.warnings, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+}
+
+// skipStatement consumes the tokens up to and including the next ';'
+func (p *sqlParser) skipStatement() {
+	for {
+		item := p.next()
+		if item.kind == tokenEOF || item.isPunct(";") {
+			return
+		}
+	}
+}
+
+// group consumes a parenthesized list and returns its comma separated
+// elements, the opening parenthesis has to be the next token
+func (p *sqlParser) group() ([][]token, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	elements := make([][]token, 0)
+	element := make([]token, 0)
+	depth := 0
+	for {
+		item := p.next()
+		switch {
+		case item.kind == tokenEOF:
+			return nil, fmt.Errorf("missing ')'")
+		case item.isPunct("("):
+			depth++
+		case item.isPunct(")") && depth == 0:
+			if len(element) > 0 {
+				elements = append(elements, element)
+			}
+			return elements, nil
+		case item.isPunct(")"):
+			depth--
+		case item.isPunct(",") && depth == 0:
+			elements = append(elements, element)
+			element = make([]token, 0)
+			continue
+		}
+		element = append(element, item)
+	}
+}
+
+// qualifiedName consumes a name like scheme.table, the default
+// scheme of PostgreSQL is dropped to match tables without scheme
+func (p *sqlParser) qualifiedName() (tableName, error) {
+	parts := make([]string, 0, 2)
+	for {
+		item := p.next()
+		if !item.isName() {
+			return tableName{}, fmt.Errorf("found %q, expected name", item.value)
+		}
+		parts = append(parts, item.value)
+		if !p.acceptPunct(".") {
+			break
+		}
+	}
+	return splitName(parts), nil
+}
+
+func splitName(parts []string) tableName {
+	name := tableName{name: parts[len(parts)-1]}
+	if len(parts) > 1 {
+		name.scheme = parts[len(parts)-2]
+	}
+	if name.scheme == symbols.DefaultScheme {
+		name.scheme = ""
+	}
+	return name
+}
+
+func (p *sqlParser) table(name tableName) (*symbols.Table, bool) {
+	return p.storage.ResolveTable(name.scheme, name.name)
+}
+
+// text returns the source of the tokens
+func (p *sqlParser) text(items []token) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return string(p.src[items[0].start:items[len(items)-1].end])
+}
+
+func (p *sqlParser) statement() error {
+	switch {
+	case p.acceptPunct(";"):
+		return nil
+	case p.peek().is("CREATE"):
+		return p.create()
+	case p.accept("ALTER", "TABLE"):
+		return p.alterTable()
+	case p.accept("COMMENT", "ON"):
+		return p.comment()
+	case p.peek().is("INSERT", "REPLACE", "COPY"):
+		p.skipData()
+		return nil
+	}
+	p.skipStatement()
+	return nil
+}
+
+// skipData skips an INSERT, REPLACE or COPY statement and warns
+// once per table that its rows are not imported
+func (p *sqlParser) skipData() {
+	line := p.peek().line
+	kind := strings.ToUpper(p.next().value)
+	p.accept("INTO")
+	name, err := p.qualifiedName()
+	p.skipStatement()
+	if err != nil {
+		p.warn(line, "skipped %s statement", kind)
+		return
+	}
+	if p.skippedData == nil {
+		p.skippedData = make(map[tableName]bool)
+	}
+	if !p.skippedData[name] {
+		p.skippedData[name] = true
+		p.warn(line, "skipped %s statements of table %s, rows are not imported", kind, name.name)
+	}
+}
+
+func (p *sqlParser) create() error {
+	p.next()
+	p.accept("OR", "REPLACE")
+	unique := p.accept("UNIQUE")
+	for p.accept("GLOBAL") || p.accept("LOCAL") || p.accept("TEMPORARY") || p.accept("TEMP") || p.accept("UNLOGGED") {
+	}
+	switch {
+	case !unique && p.accept("TABLE"):
+		return p.createTable()
+	case !unique && p.accept("TYPE"):
+		return p.createType()
+	case p.accept("INDEX"):
+		return p.createIndex(unique)
+	case !p.peek().is("SCHEMA", "DATABASE"):
+		// views, sequences, functions, triggers, extensions, ...
+		p.warn(p.peek().line, "skipped CREATE %s statement", strings.ToUpper(p.peek().value))
+	}
+	p.skipStatement()
+	return nil
+}
+
+func (p *sqlParser) createTable() error {
+	p.accept("IF", "NOT", "EXISTS")
+	line := p.peek().line
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if !p.peek().isPunct("(") {
+		// CREATE TABLE ... AS, LIKE or PARTITION OF
+		p.warn(line, "skipped table %s without column definitions", name.name)
+		p.skipStatement()
+		return nil
+	}
+	table := &symbols.Table{Scheme: name.scheme, Name: name.name}
+	p.storage.PutTable(table)
+
+	elements, err := p.group()
+	if err != nil {
+		return err
+	}
+	for _, element := range elements {
+		if err := p.tableElement(table, element); err != nil {
+			return err
+		}
+	}
+
+	// table options like ENGINE=InnoDB COMMENT='...'
+	for !p.peek().kindOf(tokenEOF) && !p.peek().isPunct(";") {
+		if p.accept("COMMENT") {
+			p.acceptPunct("=")
+			if p.peek().kind == tokenString {
+				table.Note = p.next().value
+			}
+			continue
+		}
+		p.next()
+	}
+	p.acceptPunct(";")
+	return nil
+}
+
+// tableElement declares a column or a table constraint
+func (p *sqlParser) tableElement(table *symbols.Table, element []token) error {
+	if len(element) == 0 {
+		return nil
+	}
+	e := p.sub(element)
+	first := e.peek()
+	constraintName := ""
+	if e.accept("CONSTRAINT") {
+		constraintName = e.next().value
+	}
+
+	switch {
+	case e.accept("PRIMARY", "KEY"):
+		columns, err := e.columnList()
+		if err != nil {
+			return err
+		}
+		p.primaryKey(table, columns)
+	case e.accept("UNIQUE"):
+		if !e.accept("KEY") {
+			e.accept("INDEX")
+		}
+		name := constraintName
+		if e.peek().isName() {
+			name = e.next().value
+		}
+		columns, err := e.columnList()
+		if err != nil {
+			return err
+		}
+		p.unique(table, name, columns)
+	case e.accept("FOREIGN", "KEY"):
+		if e.peek().isName() {
+			// MySQL index name
+			e.next()
+		}
+		return p.tableForeignKey(e, table, constraintName, first.line)
+	case e.peek().is("KEY", "INDEX") && constraintName == "":
+		e.next()
+		index := &symbols.Index{}
+		if e.peek().isName() {
+			index.Name = e.next().value
+		}
+		if e.accept("USING") {
+			index.Type = strings.ToLower(e.next().value)
+		}
+		columns, err := e.indexColumns()
+		if err != nil {
+			return err
+		}
+		index.Columns = columns
+		if e.accept("USING") {
+			index.Type = strings.ToLower(e.next().value)
+		}
+		table.Indexes = append(table.Indexes, index)
+	case e.peek().is("FULLTEXT", "SPATIAL") && constraintName == "":
+		p.warn(first.line, "skipped %s index of table %s", strings.ToLower(e.peek().value), table.Name)
+	case e.peek().is("CHECK", "EXCLUDE", "LIKE"):
+		p.warn(first.line, "skipped %s constraint of table %s", strings.ToUpper(e.peek().value), table.Name)
+	case constraintName != "":
+		return fmt.Errorf("unexpected %q in constraint %s", e.peek().value, constraintName)
+	default:
+		return p.column(e, table)
+	}
+	return nil
+}
+
+// columnList consumes a parenthesized list of column names
+func (p *sqlParser) columnList() ([]string, error) {
+	elements, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, len(elements))
+	for _, element := range elements {
+		if len(element) == 0 || !element[0].isName() {
+			return nil, fmt.Errorf("found %q, expected column name", p.text(element))
+		}
+		columns = append(columns, element[0].value)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("found %q, expected column names", "()")
+	}
+	return columns, nil
+}
+
+// indexColumns consumes the columns of an index, expressions
+// are returned in backticks like dbml index expressions
+func (p *sqlParser) indexColumns() ([]string, error) {
+	elements, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, len(elements))
+	for _, element := range elements {
+		columns = append(columns, p.indexColumn(element))
+	}
+	return columns, nil
+}
+
+func (p *sqlParser) indexColumn(element []token) string {
+	if len(element) == 0 {
+		return ""
+	}
+	if element[0].isName() {
+		modifiers := element[1:]
+		if isPrefixLength(element) {
+			modifiers = element[4:]
+		}
+		if isIndexModifiers(modifiers) {
+			return element[0].value
+		}
+	}
+	expression := element
+	if element[0].isPunct("(") && element[len(element)-1].isPunct(")") {
+		expression = element[1 : len(element)-1]
+	}
+	return "`" + p.text(expression) + "`"
+}
+
+// isIndexModifiers reports whether items only hold the collation,
+// operator class, sort order and null order of an index column
+func isIndexModifiers(items []token) bool {
+	i := 0
+	// skipName returns the index after the dotted name at items[at]
+	skipName := func(at int) int {
+		for at++; at+1 < len(items) && items[at].isPunct(".") && items[at+1].isName(); at += 2 {
+		}
+		return at
+	}
+	if i+1 < len(items) && items[i].is("COLLATE") && items[i+1].isName() {
+		i = skipName(i + 1)
+	}
+	if i < len(items) && items[i].isName() && !items[i].is("ASC", "DESC", "NULLS") {
+		// operator class like text_pattern_ops
+		i = skipName(i)
+	}
+	if i < len(items) && items[i].is("ASC", "DESC") {
+		i++
+	}
+	if i+1 < len(items) && items[i].is("NULLS") && items[i+1].is("FIRST", "LAST") {
+		i += 2
+	}
+	return i == len(items)
+}
+
+// isPrefixLength reports whether element is a MySQL
+// column prefix like name(10)
+func isPrefixLength(element []token) bool {
+	return len(element) >= 4 && element[2].kind == tokenNumber && element[3].isPunct(")")
+}
+
+func (p *sqlParser) primaryKey(table *symbols.Table, columns []string) {
+	if len(columns) == 1 {
+		if column, exists := table.ColumnByName(columns[0]); exists {
+			addFlag(column, "pk")
+			return
+		}
+	}
+	table.Indexes = append(table.Indexes, &symbols.Index{Columns: columns, PrimaryKey: true})
+}
+
+func (p *sqlParser) unique(table *symbols.Table, name string, columns []string) {
+	if len(columns) == 1 && name == "" {
+		if column, exists := table.ColumnByName(columns[0]); exists {
+			addFlag(column, "unique")
+			return
+		}
+	}
+	table.Indexes = append(table.Indexes, &symbols.Index{Columns: columns, Unique: true, Name: name})
+}
+
+func (p *sqlParser) tableForeignKey(e *sqlParser, table *symbols.Table, name string, line int) error {
+	columns, err := e.columnList()
+	if err != nil {
+		return err
+	}
+	if !e.accept("REFERENCES") {
+		return fmt.Errorf("found %q, expected REFERENCES", e.peek().value)
+	}
+	target, err := e.qualifiedName()
+	if err != nil {
+		return err
+	}
+	targetColumns := make([]string, len(columns))
+	if e.peek().isPunct("(") {
+		if targetColumns, err = e.columnList(); err != nil {
+			return err
+		}
+	}
+	if len(columns) != 1 || len(targetColumns) != 1 {
+		p.warn(line, "skipped composite foreign key of table %s", table.Name)
+		return nil
+	}
+	p.refs = append(p.refs, &foreignKey{
+		name:   name,
+		from:   tableName{table.Scheme, table.Name},
+		column: columns[0],
+		to:     target,
+		target: targetColumns[0],
+		line:   line,
+	})
+	return nil
+}
+
+// columnConstraints are the keywords ending a column type
+var columnConstraints = []string{
+	"NOT", "NULL", "PRIMARY", "UNIQUE", "DEFAULT", "REFERENCES", "AUTO_INCREMENT", "AUTOINCREMENT",
+	"GENERATED", "IDENTITY", "COMMENT", "CHECK", "CONSTRAINT", "COLLATE", "CHARSET", "ON", "KEY",
+}
+
+func (p *sqlParser) column(e *sqlParser, table *symbols.Table) error {
+	nameItem := e.next()
+	if !nameItem.isName() {
+		return fmt.Errorf("found %q, expected column name", nameItem.value)
+	}
+	column := &symbols.Column{Name: nameItem.value}
+	table.Columns = append(table.Columns, column)
+
+	typeTokens := make([]token, 0)
+	for item := e.peek(); !item.kindOf(tokenEOF); item = e.peek() {
+		if item.is(columnConstraints...) || (item.is("CHARACTER") && e.peekAt(1).is("SET")) {
+			break
+		}
+		typeTokens = append(typeTokens, e.next())
+	}
+	columnType, increment, err := p.columnType(table, column, typeTokens)
+	if err != nil {
+		return err
+	}
+	column.Type = columnType
+	if increment {
+		addFlag(column, "increment")
+	}
+
+	for item := e.peek(); !item.kindOf(tokenEOF); item = e.peek() {
+		switch {
+		case e.accept("NOT", "NULL"):
+			addFlag(column, "not null")
+		case e.accept("NULL"):
+		case e.accept("PRIMARY", "KEY"), e.accept("KEY"):
+			addFlag(column, "pk")
+		case e.accept("UNIQUE"):
+			e.accept("KEY")
+			addFlag(column, "unique")
+		case e.accept("DEFAULT"):
+			p.columnDefault(column, e.until(columnConstraints...))
+		case e.accept("REFERENCES"):
+			target, err := e.qualifiedName()
+			if err != nil {
+				return err
+			}
+			ref := &foreignKey{inline: true, from: tableName{table.Scheme, table.Name}, column: column.Name, to: target, line: item.line}
+			if e.peek().isPunct("(") {
+				columns, err := e.columnList()
+				if err != nil {
+					return err
+				}
+				ref.target = columns[0]
+			}
+			p.refs = append(p.refs, ref)
+			e.until(columnConstraints...)
+		case e.accept("AUTO_INCREMENT"), e.accept("AUTOINCREMENT"):
+			addFlag(column, "increment")
+		case e.accept("GENERATED"):
+			// ALWAYS AS IDENTITY, BY DEFAULT AS IDENTITY or
+			// ALWAYS AS (expression) STORED for computed columns
+			if !e.accept("ALWAYS") {
+				e.accept("BY", "DEFAULT")
+			}
+			e.accept("AS")
+			if e.accept("IDENTITY") {
+				addFlag(column, "increment")
+			}
+			e.until(columnConstraints...)
+		case e.accept("IDENTITY"):
+			addFlag(column, "increment")
+			e.until(columnConstraints...)
+		case e.accept("COMMENT"):
+			if e.peek().kind == tokenString {
+				setNote(column, e.next().value)
+			}
+		case e.accept("CONSTRAINT"):
+			e.next()
+		case e.accept("CHECK"):
+			p.warn(item.line, "skipped CHECK constraint of column %s.%s", table.Name, column.Name)
+			e.until(columnConstraints...)
+		default:
+			// COLLATE, CHARACTER SET, ON UPDATE
+			e.next()
+			e.until(columnConstraints...)
+		}
+	}
+	return nil
+}
+
+// until consumes the tokens up to one of the keywords at depth 0
+func (p *sqlParser) until(words ...string) []token {
+	items := make([]token, 0)
+	depth := 0
+	for item := p.peek(); !item.kindOf(tokenEOF); item = p.peek() {
+		if depth == 0 && item.is(words...) {
+			break
+		}
+		if item.isPunct("(") {
+			depth++
+		} else if item.isPunct(")") {
+			depth--
+		}
+		items = append(items, p.next())
+	}
+	return items
+}
+
+// typeNames are multi-word sql types and their dbml name
+var typeNames = map[string]string{
+	"character varying":           "varchar",
+	"character":                   "char",
+	"double precision":            "double",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"bit varying":                 "varbit",
+}
+
+// serialTypes are the PostgreSQL auto-increment types
+var serialTypes = map[string]string{
+	"smallserial": "smallint",
+	"serial":      "int",
+	"bigserial":   "bigint",
+}
+
+// columnType returns the dbml type of a column and whether the type
+// implies auto-increment. Inline MySQL enums are declared as enums
+// named table_column.
+func (p *sqlParser) columnType(table *symbols.Table, column *symbols.Column, items []token) (string, bool, error) {
+	if len(items) == 0 {
+		return "", false, fmt.Errorf("missing type of column %s", column.Name)
+	}
+	if items[0].is("ENUM") && len(items) > 1 && items[1].isPunct("(") {
+		enum := &symbols.Enum{Scheme: table.Scheme, Name: table.Name + "_" + column.Name}
+		for _, item := range items[2:] {
+			if item.kind == tokenString {
+				enum.Values = append(enum.Values, &symbols.EnumValue{Name: item.value})
+			}
+		}
+		p.storage.AddEnum(enum)
+		return enum.Name, false, nil
+	}
+
+	words := make([]string, 0)
+	modifiers := make([]string, 0)
+	args := ""
+	array := ""
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		switch {
+		case item.isPunct("("):
+			end := i
+			for end < len(items) && !items[end].isPunct(")") {
+				end++
+			}
+			for _, arg := range items[i+1 : end] {
+				args += arg.value
+			}
+			args = "(" + args + ")"
+			i = end
+		case item.isPunct("["):
+			array += "[]"
+			for i < len(items) && !items[i].isPunct("]") {
+				i++
+			}
+		case item.isPunct("."):
+			// scheme of an enum type, public.status is status
+			if len(words) > 0 && words[len(words)-1] == symbols.DefaultScheme {
+				words = words[:len(words)-1]
+			} else if len(words) > 0 {
+				words[len(words)-1] += "."
+			}
+		case item.kind == tokenIdent:
+			words = append(words, item.value)
+		case item.is("UNSIGNED", "SIGNED", "ZEROFILL"):
+			modifiers = append(modifiers, strings.ToLower(item.value))
+		default:
+			words = append(words, strings.ToLower(item.value))
+		}
+	}
+
+	name := strings.ReplaceAll(strings.Join(words, " "), ". ", ".")
+	if mapped, exists := typeNames[name]; exists {
+		name = mapped
+	}
+	if mapped, exists := serialTypes[name]; exists && array == "" {
+		return mapped, true, nil
+	}
+	columnType := name + args + array
+	if len(modifiers) > 0 {
+		columnType += " " + strings.Join(modifiers, " ")
+	}
+	return columnType, false, nil
+}
+
+// columnDefault sets the dbml default of the expression,
+// sequence defaults of serial columns set increment instead
+func (p *sqlParser) columnDefault(column *symbols.Column, items []token) {
+	for len(items) > 2 && items[0].isPunct("(") && items[len(items)-1].isPunct(")") {
+		items = items[1 : len(items)-1]
+	}
+	// casts like 'active'::character varying
+	for i, item := range items {
+		if item.isPunct("::") && i == 1 {
+			items = items[:1]
+			break
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	value := ""
+	first := items[0]
+	switch {
+	case first.is("nextval"):
+		addFlag(column, "increment")
+		return
+	case len(items) == 1 && first.kind == tokenString:
+		value = "'" + strings.ReplaceAll(first.value, "'", `\'`) + "'"
+	case len(items) == 1 && first.kind == tokenNumber:
+		value = first.value
+	case len(items) == 2 && first.isPunct("-") && items[1].kind == tokenNumber:
+		value = "-" + items[1].value
+	case len(items) == 1 && first.is("TRUE", "FALSE", "NULL"):
+		value = strings.ToLower(first.value)
+	default:
+		value = "`" + p.text(items) + "`"
+	}
+	column.Constraints = append(column.Constraints, &symbols.Constraint{Key: "default", Value: value})
+}
+
+func (p *sqlParser) createType() error {
+	line := p.peek().line
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if !p.accept("AS", "ENUM") {
+		p.warn(line, "skipped type %s, only enums are imported", name.name)
+		p.skipStatement()
+		return nil
+	}
+	elements, err := p.group()
+	if err != nil {
+		return err
+	}
+	enum := &symbols.Enum{Scheme: name.scheme, Name: name.name}
+	for _, element := range elements {
+		if len(element) == 1 && element[0].kind == tokenString {
+			enum.Values = append(enum.Values, &symbols.EnumValue{Name: element[0].value})
+		}
+	}
+	p.storage.AddEnum(enum)
+	p.skipStatement()
+	return nil
+}
+
+func (p *sqlParser) createIndex(unique bool) error {
+	line := p.peek().line
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	index := &symbols.Index{Unique: unique}
+	if !p.peek().is("ON") {
+		index.Name = p.next().value
+	}
+	if !p.accept("ON") {
+		return fmt.Errorf("found %q, expected ON", p.peek().value)
+	}
+	p.accept("ONLY")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if p.accept("USING") {
+		index.Type = strings.ToLower(p.next().value)
+	}
+	columns, err := p.indexColumns()
+	if err != nil {
+		return err
+	}
+	index.Columns = columns
+	if p.accept("USING") {
+		index.Type = strings.ToLower(p.next().value)
+	}
+	if p.peek().is("WHERE") {
+		p.warn(line, "imported partial index %s without its condition", index.Name)
+	}
+	p.skipStatement()
+
+	table, exists := p.table(name)
+	if !exists {
+		p.warn(line, "skipped index %s of undeclared table %s", index.Name, name.name)
+		return nil
+	}
+	table.Indexes = append(table.Indexes, index)
+	return nil
+}
+
+func (p *sqlParser) alterTable() error {
+	p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	line := p.peek().line
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	actions := p.statementElements()
+	table, exists := p.table(name)
+	if !exists {
+		p.warn(line, "skipped ALTER TABLE of undeclared table %s", name.name)
+		return nil
+	}
+
+	for _, action := range actions {
+		e := p.sub(action)
+		switch {
+		case e.accept("ADD"):
+			e.accept("COLUMN")
+			e.accept("IF", "NOT", "EXISTS")
+			if err := p.tableElement(table, e.tokens[e.pos:len(e.tokens)-1]); err != nil {
+				return err
+			}
+		case e.accept("ALTER"):
+			e.accept("COLUMN")
+			column, exists := table.ColumnByName(e.next().value)
+			if !exists {
+				continue
+			}
+			switch {
+			case e.accept("SET", "DEFAULT"):
+				p.columnDefault(column, e.until())
+			case e.accept("SET", "NOT", "NULL"):
+				addFlag(column, "not null")
+			case e.accept("ADD", "GENERATED"):
+				addFlag(column, "increment")
+			}
+		}
+	}
+	return nil
+}
+
+// statementElements consumes the rest of the statement
+// and splits it at the commas on depth 0
+func (p *sqlParser) statementElements() [][]token {
+	elements := make([][]token, 0)
+	element := make([]token, 0)
+	depth := 0
+	for {
+		item := p.next()
+		switch {
+		case item.kind == tokenEOF, item.isPunct(";"):
+			if len(element) > 0 {
+				elements = append(elements, element)
+			}
+			return elements
+		case item.isPunct("("):
+			depth++
+		case item.isPunct(")"):
+			depth--
+		case item.isPunct(",") && depth == 0:
+			elements = append(elements, element)
+			element = make([]token, 0)
+			continue
+		}
+		element = append(element, item)
+	}
+}
+
+// sub returns a parser reading items
+func (p *sqlParser) sub(items []token) *sqlParser {
+	end := token{kind: tokenEOF}
+	if len(items) > 0 {
+		last := items[len(items)-1]
+		end = token{kind: tokenEOF, line: last.line, start: last.end, end: last.end}
+	}
+	return &sqlParser{src: p.src, tokens: append(items[:len(items):len(items)], end)}
+}
+
+func (p *sqlParser) comment() error {
+	line := p.peek().line
+	kind := p.next()
+	parts := make([]string, 0, 3)
+	for p.peek().isName() && !p.peek().is("IS") {
+		parts = append(parts, p.next().value)
+		p.acceptPunct(".")
+	}
+	if !p.accept("IS") || p.peek().kind != tokenString || len(parts) == 0 {
+		p.skipStatement()
+		return nil
+	}
+	note := p.next().value
+	p.skipStatement()
+
+	switch {
+	case kind.is("TABLE"):
+		if table, exists := p.table(splitName(parts)); exists {
+			table.Note = note
+			return nil
+		}
+	case kind.is("COLUMN") && len(parts) > 1:
+		if table, exists := p.table(splitName(parts[:len(parts)-1])); exists {
+			if column, exists := table.ColumnByName(parts[len(parts)-1]); exists {
+				setNote(column, note)
+				return nil
+			}
+		}
+	default:
+		p.warn(line, "skipped comment on %s", strings.ToLower(kind.value))
+		return nil
+	}
+	p.warn(line, "skipped comment on undeclared %s", strings.Join(parts, "."))
+	return nil
+}
+
+func addFlag(column *symbols.Column, flag string) {
+	if !column.Has(flag) {
+		column.Constraints = append(column.Constraints, &symbols.Constraint{Value: flag})
+	}
+}
+
+func setNote(column *symbols.Column, note string) {
+	for _, constraint := range column.Constraints {
+		if constraint.Key == "note" {
+			constraint.Value = note
+			return
+		}
+	}
+	column.Constraints = append(column.Constraints, &symbols.Constraint{Key: "note", Value: note})
+}
+
+// resolveRefs adds the foreign keys as relationships once all
+// tables are declared. References to a unique column are one-to-one.
+// A foreign key declared twice, e.g. inline and by ALTER TABLE, is
+// added once and keeps the constraint name of either declaration.
+func (p *sqlParser) resolveRefs() {
+	added := make(map[string]*symbols.Relationship)
+	for _, ref := range p.refs {
+		from, exists := p.table(ref.from)
+		if !exists {
+			continue
+		}
+		target := ref.target
+		if target == "" {
+			to, exists := p.table(ref.to)
+			if !exists {
+				p.warn(ref.line, "skipped reference to undeclared table %s", ref.to.name)
+				continue
+			}
+			primaryKey := primaryKeyColumns(to)
+			if len(primaryKey) != 1 {
+				p.warn(ref.line, "skipped reference to table %s without single column primary key", ref.to.name)
+				continue
+			}
+			target = primaryKey[0]
+		}
+
+		toName := symbols.QualifiedName(ref.to.scheme, ref.to.name)
+		if to, exists := p.table(ref.to); exists {
+			toName = to.QualifiedName()
+		}
+		key := strings.Join([]string{from.QualifiedName(), ref.column, toName, target}, "\x00")
+		if rel, exists := added[key]; exists {
+			if rel.Name == "" && ref.name != "" {
+				// named refs are declared standalone
+				rel.Name = ref.name
+				rel.Inline = false
+			}
+			continue
+		}
+
+		relType := ">"
+		if column, exists := from.ColumnByName(ref.column); exists && (column.IsPrimaryKey() || column.Has("unique")) {
+			relType = "-"
+		}
+		rel := &symbols.Relationship{
+			Name:    ref.name,
+			SchemeA: from.Scheme,
+			TableA:  from.Name,
+			ColumnA: ref.column,
+			SchemeB: ref.to.scheme,
+			TableB:  ref.to.name,
+			ColumnB: target,
+			Type:    relType,
+			Inline:  ref.inline,
+		}
+		added[key] = rel
+		from.References = append(from.References, rel)
+	}
+}
+
+func primaryKeyColumns(table *symbols.Table) []string {
+	for _, index := range table.Indexes {
+		if index.PrimaryKey {
+			return index.Columns
+		}
+	}
+	columns := make([]string, 0)
+	for _, column := range table.Columns {
+		if column.IsPrimaryKey() {
+			columns = append(columns, column.Name)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,137 @@
+package importer_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/h0rzn/dbml-lsp/export"
+	"github.com/h0rzn/dbml-lsp/importer"
+	"github.com/h0rzn/dbml-lsp/parser"
+	explicitparser "github.com/h0rzn/dbml-lsp/parser/explicit_parser"
+)
+
+func TestIndexColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		columns []string
+	}{
+		{"postgres column", "CREATE INDEX e1 ON a (x);", []string{"x"}},
+		{"postgres columns", "CREATE INDEX e1 ON a (x, y);", []string{"x", "y"}},
+		{"postgres sum", "CREATE INDEX e1 ON a (x + y);", []string{"`x + y`"}},
+		{"postgres concatenation", "CREATE INDEX e1 ON a (x || y);", []string{"`x || y`"}},
+		{"postgres function", "CREATE INDEX e1 ON a (lower(x));", []string{"`lower(x)`"}},
+		{"postgres parenthesized", "CREATE INDEX e1 ON a ((x + 1));", []string{"`x + 1`"}},
+		{"postgres cast", "CREATE INDEX e1 ON a (x::text);", []string{"`x::text`"}},
+		{"postgres sort order", "CREATE INDEX e1 ON a (x DESC NULLS LAST, y ASC);", []string{"x", "y"}},
+		{"postgres operator class", "CREATE INDEX e1 ON a USING btree (x text_pattern_ops);", []string{"x"}},
+		{"postgres qualified operator class", "CREATE INDEX e1 ON a (x public.text_ops DESC);", []string{"x"}},
+		{"postgres collation", `CREATE INDEX e1 ON a (x COLLATE "C" text_pattern_ops NULLS FIRST);`, []string{"x"}},
+		{"postgres qualified collation", `CREATE INDEX e1 ON a (x COLLATE pg_catalog."default");`, []string{"x"}},
+		{"postgres expression with modifier", "CREATE INDEX e1 ON a (lower(x) DESC);", []string{"`lower(x) DESC`"}},
+		{"mysql prefix length", "CREATE INDEX e1 ON a (x(10), y);", []string{"x", "y"}},
+		{"mysql prefix length with order", "CREATE INDEX e1 ON a (x(10) DESC);", []string{"x"}},
+		{"mysql quoted column", "CREATE INDEX e1 ON a (`x` DESC);", []string{"x"}},
+		{"mysql functional", "CREATE INDEX e1 ON a ((x + y));", []string{"`x + y`"}},
+		{"mysql inline", "CREATE TABLE b (x int, y int, KEY k (x + y));", []string{"`x + y`"}},
+		{"mysql inline prefix", "CREATE TABLE b (x text, KEY k (x(8)));", []string{"x"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql := "CREATE TABLE a (x int, y int);\n" + test.sql
+			storage, _, err := importer.SQL(sql)
+			if err != nil {
+				t.Fatal(err)
+			}
+			table, exists := storage.TableByName("b")
+			if !exists {
+				table, _ = storage.TableByName("a")
+			}
+			if len(table.Indexes) != 1 {
+				t.Fatalf("expected one index, got %d", len(table.Indexes))
+			}
+			if columns := table.Indexes[0].Columns; !slices.Equal(columns, test.columns) {
+				t.Errorf("expected columns %q, got %q", test.columns, columns)
+			}
+		})
+	}
+}
+
+func TestSkippedStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		warnings []string
+	}{
+		{"insert", "INSERT INTO a VALUES (1);\nINSERT INTO a VALUES (2);", []string{
+			"line 2: skipped INSERT statements of table a, rows are not imported",
+		}},
+		{"copy", "COPY public.a (x) FROM stdin;", []string{
+			"line 2: skipped COPY statements of table a, rows are not imported",
+		}},
+		{"view", "CREATE VIEW v AS SELECT x FROM a;", []string{"line 2: skipped CREATE VIEW statement"}},
+		{"replaced view", "CREATE OR REPLACE VIEW v AS SELECT x FROM a;", []string{"line 2: skipped CREATE VIEW statement"}},
+		{"function", "CREATE FUNCTION f() RETURNS int AS 'select 1' LANGUAGE sql;", []string{
+			"line 2: skipped CREATE FUNCTION statement",
+		}},
+		{"table check", "CREATE TABLE b (x int, CHECK (x > 0));", []string{"line 2: skipped CHECK constraint of table b"}},
+		{"column check", "CREATE TABLE b (x int CHECK (x > 0) NOT NULL);", []string{
+			"line 2: skipped CHECK constraint of column b.x",
+		}},
+		{"schema", "CREATE SCHEMA s;", nil},
+		{"session", "SET search_path = public;\nBEGIN;\nCOMMIT;", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql := "CREATE TABLE a (x int);\n" + test.sql
+			_, warnings, err := importer.SQL(sql)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(warnings, test.warnings) {
+				t.Errorf("expected warnings\n%s\ngot\n%s", strings.Join(test.warnings, "\n"), strings.Join(warnings, "\n"))
+			}
+		})
+	}
+}
+
+// TestRoundTrip imports names that are no dbml identifiers,
+// exports them as dbml and parses the export again
+func TestRoundTrip(t *testing.T) {
+	sql := `CREATE TYPE "order state" AS ENUM ('in progress', 'done');
+CREATE TABLE "order items" (
+  "order id" int PRIMARY KEY,
+  "note" text,
+  state "order state"
+);
+CREATE TABLE sales."Table" (
+  id int PRIMARY KEY,
+  item int REFERENCES "order items" ("order id")
+);
+CREATE INDEX "by note" ON "order items" ("note" DESC, state);
+ALTER TABLE sales."Table" ADD CONSTRAINT "fk item" FOREIGN KEY (id) REFERENCES "order items" ("order id");
+`
+	storage, _, err := importer.SQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbml, err := export.DBML(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := parser.NewParser(explicitparser.NewParser()).ParseText(dbml)
+	if err != nil {
+		t.Fatalf("parse exported dbml: %s\n%s", err, dbml)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Fatalf("parse exported dbml: %v\n%s", result.Diagnostics, dbml)
+	}
+	reexported, err := export.DBML(result.Symbols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reexported != dbml {
+		t.Errorf("parsed export differs from the export\n%s\ngot\n%s", dbml, reexported)
+	}
+}
@@ -0,0 +1,258 @@
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	_ "modernc.org/sqlite"
+)
+
+// SQLite reads the schema of the SQLite database file at path from
+// sqlite_master and the table_info, foreign_key_list and index_list
+// pragmas. The database is opened read-only.
+func SQLite(path string) (*symbols.Storage, []string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+url.PathEscape(path)+"?mode=ro")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer db.Close()
+
+	s := &sqliteReader{
+		db:     db,
+		parser: &sqlParser{storage: symbols.NewStorage()},
+	}
+	if err := s.read(); err != nil {
+		return nil, s.parser.warnings, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	s.parser.resolveRefs()
+	return s.parser.storage, s.parser.warnings, nil
+}
+
+type sqliteReader struct {
+	db *sql.DB
+	// parser holds the storage, the references and warnings
+	// and parses types, defaults and CREATE INDEX statements
+	parser *sqlParser
+}
+
+type schemaEntry struct {
+	kind    string
+	name    string
+	table   string
+	sql     string
+	defined bool
+}
+
+func (s *sqliteReader) read() error {
+	rows, err := s.db.Query(`SELECT type, name, tbl_name, sql FROM sqlite_master
+		WHERE type IN ('table', 'index') AND name NOT LIKE 'sqlite_%' ORDER BY rowid`)
+	if err != nil {
+		return err
+	}
+	entries := make([]schemaEntry, 0)
+	for rows.Next() {
+		var entry schemaEntry
+		var definition sql.NullString
+		if err := rows.Scan(&entry.kind, &entry.name, &entry.table, &definition); err != nil {
+			rows.Close()
+			return err
+		}
+		entry.sql, entry.defined = definition.String, definition.Valid
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.kind == "table" {
+			if err := s.table(entry); err != nil {
+				return err
+			}
+		}
+	}
+	for _, entry := range entries {
+		if entry.kind == "index" && entry.defined {
+			// CREATE INDEX statements, the columns
+			// of expression indexes are in the sql only
+			p := &sqlParser{src: []rune(entry.sql), tokens: lex(entry.sql), storage: s.parser.storage}
+			if err := p.statement(); err != nil {
+				s.warn("skipped index %s: %s", entry.name, err.Error())
+			}
+			for _, warning := range p.warnings {
+				// drop the line within the index sql
+				_, warning, _ = strings.Cut(warning, ": ")
+				s.warn("%s", warning)
+			}
+		}
+	}
+	return nil
+}
+
+var autoincrement = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`)
+
+func (s *sqliteReader) table(entry schemaEntry) error {
+	table := &symbols.Table{Name: entry.name}
+	s.parser.storage.PutTable(table)
+
+	primaryKey := make(map[int]string)
+	err := s.pragma("table_info", entry.name, func(rows *sql.Rows) error {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		column := &symbols.Column{Name: name, Type: "blob"}
+		table.Columns = append(table.Columns, column)
+		if columnType != "" {
+			// typeless columns have blob affinity
+			items := lex(columnType)
+			mapped, _, err := s.parser.columnType(table, column, items[:len(items)-1])
+			if err != nil {
+				return err
+			}
+			column.Type = mapped
+		}
+		if pk > 0 {
+			primaryKey[pk] = name
+		}
+		if notNull == 1 {
+			addFlag(column, "not null")
+		}
+		if defaultValue.Valid {
+			p := &sqlParser{src: []rune(defaultValue.String), tokens: lex(defaultValue.String)}
+			p.columnDefault(column, p.tokens[:len(p.tokens)-1])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(primaryKey) == 1 {
+		column, _ := table.ColumnByName(primaryKey[1])
+		addFlag(column, "pk")
+		if autoincrement.MatchString(entry.sql) {
+			addFlag(column, "increment")
+		}
+	} else if len(primaryKey) > 1 {
+		columns := make([]string, 0, len(primaryKey))
+		for i := 1; i <= len(primaryKey); i++ {
+			columns = append(columns, primaryKey[i])
+		}
+		table.Indexes = append(table.Indexes, &symbols.Index{Columns: columns, PrimaryKey: true})
+	}
+
+	if err := s.uniqueConstraints(table); err != nil {
+		return err
+	}
+	return s.foreignKeys(table)
+}
+
+// uniqueConstraints declares the UNIQUE constraints of table,
+// CREATE INDEX statements are read from sqlite_master instead
+func (s *sqliteReader) uniqueConstraints(table *symbols.Table) error {
+	names := make([]string, 0)
+	err := s.pragma("index_list", table.Name, func(rows *sql.Rows) error {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			return err
+		}
+		if origin == "u" {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// index_list returns the latest index first
+	for i := len(names) - 1; i >= 0; i-- {
+		columns := make([]string, 0)
+		err := s.pragma("index_info", names[i], func(rows *sql.Rows) error {
+			var seqno, cid int
+			var name sql.NullString
+			if err := rows.Scan(&seqno, &cid, &name); err != nil {
+				return err
+			}
+			columns = append(columns, name.String)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		s.parser.unique(table, "", columns)
+	}
+	return nil
+}
+
+func (s *sqliteReader) foreignKeys(table *symbols.Table) error {
+	keys := make(map[int]*foreignKey)
+	ids := make([]int, 0)
+	composite := make(map[int]bool)
+	err := s.pragma("foreign_key_list", table.Name, func(rows *sql.Rows) error {
+		var id, seq int
+		var target, from, onUpdate, onDelete, match string
+		var to sql.NullString
+		if err := rows.Scan(&id, &seq, &target, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return err
+		}
+		if _, exists := keys[id]; exists {
+			composite[id] = true
+			return nil
+		}
+		ids = append(ids, id)
+		keys[id] = &foreignKey{
+			from:   tableName{name: table.Name},
+			column: from,
+			to:     tableName{name: target},
+			target: to.String,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// foreign_key_list returns the last declared key first
+	for i := len(ids) - 1; i >= 0; i-- {
+		if composite[ids[i]] {
+			s.warn("skipped composite foreign key of table %s", table.Name)
+			continue
+		}
+		s.parser.refs = append(s.parser.refs, keys[ids[i]])
+	}
+	return nil
+}
+
+// warn adds a warning, the definitions of the database have no line
+func (s *sqliteReader) warn(format string, args ...any) {
+	s.parser.warnings = append(s.parser.warnings, fmt.Sprintf(format, args...))
+}
+
+// pragma calls scan for each row of the pragma on name
+func (s *sqliteReader) pragma(pragma string, name string, scan func(rows *sql.Rows) error) error {
+	rows, err := s.db.Query(fmt.Sprintf(`PRAGMA %s("%s")`, pragma, strings.ReplaceAll(name, `"`, `""`)))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
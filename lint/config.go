@@ -0,0 +1,155 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+)
+
+// ConfigFileName is looked up in the workspace root
+const ConfigFileName = ".dbmllint.json"
+
+// Config is the lint configuration, e.g.
+//
+//	{
+//		"rules": {
+//			"table-snake-case": {"severity": "error"},
+//			"max-columns": {"options": {"max": 20}},
+//			"table-note": {"severity": "off"}
+//		}
+//	}
+type Config struct {
+	Rules map[string]RuleConfig `json:"rules"`
+}
+
+type RuleConfig struct {
+	// Severity is one of "error", "warning", "info", "hint" or "off".
+	// Empty uses the default severity of the rule.
+	Severity string  `json:"severity,omitempty"`
+	Options  Options `json:"options,omitempty"`
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Rules: make(map[string]RuleConfig),
+	}
+}
+
+// LoadConfig reads the config file at path.
+// A missing file yields an empty config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+func ParseConfig(data []byte) (*Config, error) {
+	config := NewConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid lint config: %s", err.Error())
+	}
+	if config.Rules == nil {
+		config.Rules = make(map[string]RuleConfig)
+	}
+	for id, ruleConfig := range config.Rules {
+		if _, _, err := parseSeverity(ruleConfig.Severity); err != nil {
+			return nil, fmt.Errorf("rule %q: %s", id, err.Error())
+		}
+	}
+	return config, nil
+}
+
+// Merge returns a new config where rule configs of
+// other take precedence over the ones of c.
+func (c *Config) Merge(other *Config) *Config {
+	merged := NewConfig()
+	for id, ruleConfig := range c.Rules {
+		merged.Rules[id] = ruleConfig
+	}
+	if other == nil {
+		return merged
+	}
+	for id, ruleConfig := range other.Rules {
+		base := merged.Rules[id]
+		if ruleConfig.Severity != "" {
+			base.Severity = ruleConfig.Severity
+		}
+		if len(ruleConfig.Options) > 0 {
+			options := make(Options)
+			for key, value := range base.Options {
+				options[key] = value
+			}
+			for key, value := range ruleConfig.Options {
+				options[key] = value
+			}
+			base.Options = options
+		}
+		merged.Rules[id] = base
+	}
+	return merged
+}
+
+// parseSeverity returns the severity for value,
+// enabled is false for "off".
+func parseSeverity(value string) (severity analysis.Severity, enabled bool, err error) {
+	switch strings.ToLower(value) {
+	case "":
+		return 0, true, nil
+	case "off":
+		return 0, false, nil
+	case "error":
+		return analysis.SeverityError, true, nil
+	case "warning", "warn":
+		return analysis.SeverityWarning, true, nil
+	case "info", "information":
+		return analysis.SeverityInformation, true, nil
+	case "hint":
+		return analysis.SeverityHint, true, nil
+	}
+	return 0, false, fmt.Errorf("unknown severity %q", value)
+}
+
+// Options are rule specific settings
+type Options map[string]any
+
+func (o Options) Int(key string, fallback int) int {
+	// json numbers are decoded as float64
+	if value, ok := o[key].(float64); ok {
+		return int(value)
+	}
+	if value, ok := o[key].(int); ok {
+		return value
+	}
+	return fallback
+}
+
+func (o Options) String(key string, fallback string) string {
+	if value, ok := o[key].(string); ok {
+		return value
+	}
+	return fallback
+}
+
+func (o Options) Strings(key string, fallback []string) []string {
+	switch values := o[key].(type) {
+	case []string:
+		return values
+	case []any:
+		out := make([]string, 0, len(values))
+		for _, value := range values {
+			if str, ok := value.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return fallback
+}
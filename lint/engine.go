@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"sort"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Rule is a named schema convention check
+type Rule struct {
+	ID          string
+	Description string
+	// Severity is used unless overwritten by the config
+	Severity analysis.Severity
+	// Check returns the violations found in storage,
+	// the engine fills in code and severity.
+	Check func(storage *symbols.Storage, options Options) []*analysis.Diagnostic
+	// Fix optionally returns quick fixes for a diagnostic of this rule,
+	// text is the source the storage was parsed from.
+	Fix func(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix
+}
+
+type Engine struct {
+	rules  map[string]*Rule
+	config *Config
+}
+
+// NewEngine returns an engine running the builtin rules
+func NewEngine(config *Config) *Engine {
+	engine := &Engine{
+		rules:  make(map[string]*Rule),
+		config: NewConfig(),
+	}
+	for _, rule := range DefaultRules() {
+		engine.Register(rule)
+	}
+	engine.SetConfig(config)
+	return engine
+}
+
+// Register adds rule, replacing a rule with the same id
+func (e *Engine) Register(rule *Rule) {
+	e.rules[rule.ID] = rule
+}
+
+func (e *Engine) SetConfig(config *Config) {
+	if config == nil {
+		config = NewConfig()
+	}
+	e.config = config
+}
+
+func (e *Engine) Rule(id string) (*Rule, bool) {
+	rule, exists := e.rules[id]
+	return rule, exists
+}
+
+// Rules returns the registered rules ordered by id
+func (e *Engine) Rules() []*Rule {
+	rules := make([]*Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// Run checks storage against all enabled rules
+func (e *Engine) Run(storage *symbols.Storage) []*analysis.Diagnostic {
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, rule := range e.Rules() {
+		ruleConfig := e.config.Rules[rule.ID]
+		severity, enabled, err := parseSeverity(ruleConfig.Severity)
+		if !enabled || err != nil {
+			continue
+		}
+		if severity == 0 {
+			severity = rule.Severity
+		}
+
+		options := ruleConfig.Options
+		if options == nil {
+			options = make(Options)
+		}
+		for _, diagnostic := range rule.Check(storage, options) {
+			diagnostic.Code = rule.ID
+			diagnostic.Severity = severity
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	return diagnostics
+}
+
+// Fixes returns the quick fixes the producing rule offers for diagnostic
+func (e *Engine) Fixes(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix {
+	rule, exists := e.rules[diagnostic.Code]
+	if !exists || rule.Fix == nil {
+		return nil
+	}
+	return rule.Fix(storage, text, diagnostic)
+}
@@ -0,0 +1,318 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// DefaultRules returns the builtin rules
+func DefaultRules() []*Rule {
+	return []*Rule{
+		{
+			ID:          "table-snake-case",
+			Description: "table names are snake_case",
+			Severity:    analysis.SeverityWarning,
+			Check:       checkSnakeCase,
+		},
+		{
+			ID:          "table-naming",
+			Description: "table names are plural (option style: plural|singular)",
+			Severity:    analysis.SeverityHint,
+			Check:       checkTableNaming,
+		},
+		{
+			ID:          "primary-key-id",
+			Description: "every table has a primary key column named id",
+			Severity:    analysis.SeverityWarning,
+			Check:       checkPrimaryKeyID,
+			Fix:         fixPrimaryKeyID,
+		},
+		{
+			ID:          "prefer-pk-shorthand",
+			Description: "primary keys are declared as pk instead of primary key",
+			Severity:    analysis.SeverityHint,
+			Check:       checkPreferPK,
+			Fix:         fixPreferPK,
+		},
+		{
+			ID:          "foreign-key-ref",
+			Description: "columns named *_id are part of a relationship",
+			Severity:    analysis.SeverityWarning,
+			Check:       checkForeignKeyRef,
+		},
+		{
+			ID:          "table-note",
+			Description: "every table has a note",
+			Severity:    analysis.SeverityHint,
+			Check:       checkTableNote,
+			Fix:         fixTableNote,
+		},
+		{
+			ID:          "max-columns",
+			Description: "tables have at most max columns (option max, default 30)",
+			Severity:    analysis.SeverityWarning,
+			Check:       checkMaxColumns,
+		},
+		{
+			ID:          "forbidden-types",
+			Description: "columns do not use forbidden types (option types)",
+			Severity:    analysis.SeverityError,
+			Check:       checkForbiddenTypes,
+		},
+		{
+			ID:          analysis.CodeIncompatibleTypes,
+			Description: "relationship endpoints have compatible types (option equivalences)",
+			Severity:    analysis.SeverityWarning,
+			Check:       checkRelationshipTypes,
+		},
+	}
+}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+func checkSnakeCase(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		if !snakeCase.MatchString(table.Name) {
+			diagnostics = append(diagnostics, &analysis.Diagnostic{
+				Position: table.Position,
+				Message:  fmt.Sprintf("table name %q is not snake_case", table.Name),
+			})
+		}
+	}
+	return diagnostics
+}
+
+func checkTableNaming(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	style := options.String("style", "plural")
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		// naive heuristic, good enough for english table names
+		plural := strings.HasSuffix(table.Name, "s") && !strings.HasSuffix(table.Name, "ss")
+		if (style == "plural") == plural {
+			continue
+		}
+		diagnostics = append(diagnostics, &analysis.Diagnostic{
+			Position: table.Position,
+			Message:  fmt.Sprintf("table name %q should be %s", table.Name, style),
+		})
+	}
+	return diagnostics
+}
+
+func checkPrimaryKeyID(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		var primaryKeys []*symbols.Column
+		for _, column := range table.Columns {
+			if column.IsPrimaryKey() {
+				primaryKeys = append(primaryKeys, column)
+			}
+		}
+
+		if len(primaryKeys) == 0 {
+			diagnostics = append(diagnostics, &analysis.Diagnostic{
+				Position: table.Position,
+				Message:  fmt.Sprintf("table %q has no primary key", table.Name),
+			})
+			continue
+		}
+		for _, column := range primaryKeys {
+			if column.Name != "id" {
+				diagnostics = append(diagnostics, &analysis.Diagnostic{
+					Position: column.Position,
+					Message:  fmt.Sprintf("primary key %q of table %q should be named \"id\"", column.Name, table.Name),
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+func fixPrimaryKeyID(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix {
+	table, exists := storage.TableAt(diagnostic.Position.Line, diagnostic.Position.Offset)
+	if !exists || table.Position != diagnostic.Position {
+		// only missing primary keys can be fixed
+		return nil
+	}
+	if column, exists := table.ColumnByName("id"); exists {
+		settings, _, _ := analysis.ColumnSettings(text, column.Position.Line)
+		return []*analysis.Fix{{
+			Title: fmt.Sprintf("Make %s.id the primary key", table.Name),
+			Edits: []analysis.TextEdit{
+				analysis.SetColumnSettings(text, column.Position.Line, append([]string{"pk"}, settings...)),
+			},
+		}}
+	}
+	return []*analysis.Fix{{
+		Title: fmt.Sprintf("Add primary key column 'id' to %q", table.Name),
+		Edits: []analysis.TextEdit{insertAfterHead(table, "id integer [pk]")},
+	}}
+}
+
+func checkPreferPK(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		for _, column := range table.Columns {
+			for _, constraint := range column.Constraints {
+				if constraint.Key == "" && constraint.Value == "primary key" {
+					diagnostics = append(diagnostics, &analysis.Diagnostic{
+						Position: column.Position,
+						Message:  fmt.Sprintf("use pk instead of primary key for %s.%s", table.Name, column.Name),
+					})
+				}
+			}
+		}
+	}
+	return diagnostics
+}
+
+func fixPreferPK(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix {
+	position, found := analysis.FindInLine(text, diagnostic.Position.Line, "primary key")
+	if !found {
+		return nil
+	}
+	return []*analysis.Fix{{
+		Title: "Replace 'primary key' with 'pk'",
+		Edits: []analysis.TextEdit{analysis.Replace(position, "pk")},
+	}}
+}
+
+func checkForeignKeyRef(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	relationships := allRelationships(storage)
+	for _, table := range storage.Tables() {
+		for _, column := range table.Columns {
+			if !strings.HasSuffix(column.Name, "_id") {
+				continue
+			}
+			if !isReferenced(relationships, table, column) {
+				diagnostics = append(diagnostics, &analysis.Diagnostic{
+					Position: column.Position,
+					Message:  fmt.Sprintf("column %s.%s looks like a foreign key but has no ref", table.Name, column.Name),
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+func checkTableNote(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		if strings.TrimSpace(table.Note) == "" {
+			diagnostics = append(diagnostics, &analysis.Diagnostic{
+				Position: table.Position,
+				Message:  fmt.Sprintf("table %q has no note", table.Name),
+			})
+		}
+	}
+	return diagnostics
+}
+
+func fixTableNote(storage *symbols.Storage, text string, diagnostic *analysis.Diagnostic) []*analysis.Fix {
+	table, exists := storage.TableAt(diagnostic.Position.Line, diagnostic.Position.Offset)
+	if !exists {
+		return nil
+	}
+	return []*analysis.Fix{{
+		Title: fmt.Sprintf("Add note to %q", table.Name),
+		Edits: []analysis.TextEdit{insertAfterHead(table, `Note: 'TODO'`)},
+	}}
+}
+
+func checkMaxColumns(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	max := options.Int("max", 30)
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	for _, table := range storage.Tables() {
+		if len(table.Columns) > max {
+			diagnostics = append(diagnostics, &analysis.Diagnostic{
+				Position: table.Position,
+				Message:  fmt.Sprintf("table %q has %d columns, maximum is %d", table.Name, len(table.Columns), max),
+			})
+		}
+	}
+	return diagnostics
+}
+
+func checkForbiddenTypes(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	forbidden := make(map[string]bool)
+	for _, typeName := range options.Strings("types", nil) {
+		forbidden[analysis.NormalizeType(typeName)] = true
+	}
+
+	diagnostics := make([]*analysis.Diagnostic, 0)
+	if len(forbidden) == 0 {
+		return diagnostics
+	}
+	for _, table := range storage.Tables() {
+		for _, column := range table.Columns {
+			if forbidden[analysis.NormalizeType(column.Type)] {
+				diagnostics = append(diagnostics, &analysis.Diagnostic{
+					Position: column.Position,
+					Message:  fmt.Sprintf("type %q of column %s.%s is forbidden", column.Type, table.Name, column.Name),
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+// checkRelationshipTypes accepts additional type equivalences:
+//
+//	"equivalences": {"postgresql": {"integer": ["myint"]}}
+func checkRelationshipTypes(storage *symbols.Storage, options Options) []*analysis.Diagnostic {
+	equivalences := analysis.NewTypeEquivalences()
+	if databaseTypes, ok := options["equivalences"].(map[string]any); ok {
+		for databaseType, tables := range databaseTypes {
+			table, ok := tables.(map[string]any)
+			if !ok {
+				continue
+			}
+			for canonical := range table {
+				equivalences.Add(databaseType, canonical, Options(table).Strings(canonical, nil)...)
+			}
+		}
+	}
+	return analysis.CheckRelationshipTypes(storage, equivalences)
+}
+
+//
+// helpers
+//
+
+func allRelationships(storage *symbols.Storage) []*symbols.Relationship {
+	relationships := make([]*symbols.Relationship, 0)
+	for _, table := range storage.Tables() {
+		relationships = append(relationships, table.References...)
+	}
+	return relationships
+}
+
+func isReferenced(relationships []*symbols.Relationship, table *symbols.Table, column *symbols.Column) bool {
+	for _, rel := range relationships {
+		if isEndpoint(table, rel.SchemeA, rel.TableA) && rel.ColumnA == column.Name {
+			return true
+		}
+		if isEndpoint(table, rel.SchemeB, rel.TableB) && rel.ColumnB == column.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func isEndpoint(table *symbols.Table, scheme string, name string) bool {
+	if scheme != "" && scheme != table.SchemeOrDefault() {
+		return false
+	}
+	return name == table.Name || (table.Alias != "" && name == table.Alias)
+}
+
+// insertAfterHead inserts line as first line of the table body
+func insertAfterHead(table *symbols.Table, line string) analysis.TextEdit {
+	return analysis.Insert(table.Position.Line+1, 0, "\t"+line+"\n")
+}
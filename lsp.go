@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/lint"
 	"github.com/tliron/glsp"
 	protocol "github.com/tliron/glsp/protocol_3_16"
 	"github.com/tliron/glsp/server"
@@ -13,20 +16,65 @@ var (
 
 func RunLSP() {
 	handler = protocol.Handler{
-		Initialize:             initialize,
-		Initialized:            initialized,
-		Shutdown:               shutdown,
-		SetTrace:               setTrace,
-		TextDocumentCompletion: TestCompletion,
+		Initialize:                 initialize,
+		Initialized:                initialized,
+		Shutdown:                   shutdown,
+		SetTrace:                   setTrace,
+		TextDocumentCompletion:     TestCompletion,
+		TextDocumentDidOpen:        textDocumentDidOpen,
+		TextDocumentDidChange:      textDocumentDidChange,
+		TextDocumentDidClose:       textDocumentDidClose,
+		TextDocumentCodeAction:     textDocumentCodeAction,
+		TextDocumentDefinition:     textDocumentDefinition,
+		TextDocumentHover:          textDocumentHover,
+		TextDocumentDocumentSymbol: textDocumentDocumentSymbol,
+
+		WorkspaceDidChangeConfiguration: workspaceDidChangeConfiguration,
+		WorkspaceExecuteCommand:         workspaceExecuteCommand,
 	}
 	server := server.NewServer(&handler, "dbml-lsp", false)
 	server.RunStdio()
 }
 
+// serverCapabilities adds the position encoding of
+// LSP 3.17 to the capabilities known to the protocol package
+type serverCapabilities struct {
+	protocol.ServerCapabilities
+	PositionEncoding string `json:"positionEncoding,omitempty"`
+}
+
+type initializeResult struct {
+	Capabilities serverCapabilities                   `json:"capabilities"`
+	ServerInfo   *protocol.InitializeResultServerInfo `json:"serverInfo,omitempty"`
+}
+
 func initialize(context *glsp.Context, params *protocol.InitializeParams) (any, error) {
-	capabilities := handler.CreateServerCapabilities()
+	capabilities := serverCapabilities{
+		ServerCapabilities: handler.CreateServerCapabilities(),
+	}
+	capabilities.ExecuteCommandProvider.Commands = []string{exportCommand, previewCommand}
+	// the protocol package does not know about position encodings yet
+	if encoding, announced := negotiatePositionEncoding(context.Params); announced {
+		positionEncoding = encoding
+		capabilities.PositionEncoding = encoding
+	}
+
+	var rootURI string
+	if params.RootURI != nil {
+		rootURI = *params.RootURI
+	} else if len(params.WorkspaceFolders) > 0 {
+		rootURI = params.WorkspaceFolders[0].URI
+	}
+	if rootURI != "" {
+		if err := loadLintConfig(rootURI); err != nil {
+			context.Notify(protocol.ServerWindowLogMessage, protocol.LogMessageParams{
+				Type:    protocol.MessageTypeWarning,
+				Message: fmt.Sprintf("%s: %s, using the default lint config", lint.ConfigFileName, err),
+			})
+		}
+	}
 
-	return protocol.InitializeResult{
+	return initializeResult{
 		Capabilities: capabilities,
 		ServerInfo: &protocol.InitializeResultServerInfo{
 			Name:    "dbml-lsp",
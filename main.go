@@ -9,33 +9,68 @@ import (
 )
 
 func main() {
-	file, err := os.Open("test.dbml")
-	if err != nil {
-		panic(err)
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "introspect" {
+		if err := runIntrospect(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		if err := runDiff(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
-	defer file.Close()
 
-	expliParser := explicitparser.NewParser(file)
-	parser := parser.NewParser(expliParser)
-	err = parser.Init()
+	text, err := os.ReadFile("test.dbml")
 	if err != nil {
 		panic(err)
 	}
-	err = parser.Parse()
+
+	parser := parser.NewParser(explicitparser.NewParser())
+	_, err = parser.ParseText(string(text))
 	if err != nil {
 		panic(err)
 	}
-	table, exists := parser.Symbols.TableByName("tableA")
+	snapshot := parser.Snapshot()
+	table, exists := snapshot.Storage.TableByName("tableA")
 	if !exists {
 		panic("table does not exists")
 	}
 	fmt.Println(table)
 	fmt.Println("---")
-	err = parser.Parse()
+	// every run reads from a fresh reader
+	_, err = parser.ParseText(string(text))
 	if err != nil {
 		panic(err)
 	}
-	table, exists = parser.Symbols.TableByName("tableA")
+	// the reparse publishes a new snapshot,
+	// the previous one stays untouched
+	fmt.Printf("snapshot v%d -> v%d\n", snapshot.Version, parser.Snapshot().Version)
+	table, exists = snapshot.Storage.TableByName("tableA")
 	if !exists {
 		panic("table does not exists")
 	}
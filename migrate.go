@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/h0rzn/dbml-lsp/diff"
+	"github.com/h0rzn/dbml-lsp/export"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// runMigrate implements 'dbml-lsp migrate [-dialect name] <old> <new>'
+// and writes the migration to stdout and its warnings to stderr. The
+// dialect defaults to the database type of the new project.
+func runMigrate(args []string) error {
+	usage := fmt.Errorf("usage: dbml-lsp migrate [-dialect name] <old.dbml> <new.dbml>")
+	dialectName := ""
+	if len(args) > 1 && args[0] == "-dialect" {
+		dialectName, args = args[1], args[2:]
+	}
+	if len(args) != 2 {
+		return usage
+	}
+	old, err := readStorage(args[0])
+	if err != nil {
+		return err
+	}
+	new, err := readStorage(args[1])
+	if err != nil {
+		return err
+	}
+
+	dialect, err := export.ProjectDialect(new)
+	if dialectName != "" {
+		databaseType, known := symbols.NormalizeDatabaseType(dialectName)
+		if !known {
+			return fmt.Errorf("unknown dialect %s", dialectName)
+		}
+		dialect, err = lookupDialect(databaseType)
+	}
+	if err != nil {
+		return err
+	}
+
+	migration, warnings, err := export.Migrate(diff.Compare(old, new), old, new, dialect)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+	_, err = fmt.Print(migration)
+	return err
+}
+
+func lookupDialect(databaseType string) (*export.Dialect, error) {
+	dialect, exists := export.DialectOf(databaseType)
+	if !exists {
+		return nil, fmt.Errorf("migrations do not support %s", databaseType)
+	}
+	return dialect, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+	"github.com/tliron/glsp"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// textDocumentDocumentSymbol lists the project,
+// tables with their columns and sticky notes.
+func textDocumentDocumentSymbol(context *glsp.Context, params *protocol.DocumentSymbolParams) (any, error) {
+	document, exists := getDocument(params.TextDocument.URI)
+	if !exists {
+		return nil, nil
+	}
+	state := document.State()
+	storage := state.Snapshot.Storage
+	mapper := newLineMapper(state.Text)
+
+	outline := make([]protocol.DocumentSymbol, 0)
+	if project := storage.GetProject(); project != nil && project.Name != "" {
+		outline = append(outline, protocol.DocumentSymbol{
+			Name:           project.Name,
+			Kind:           protocol.SymbolKindNamespace,
+			Range:          mapper.toRange(project.Position),
+			SelectionRange: mapper.toRange(project.Position),
+		})
+	}
+
+	for _, table := range storage.Tables() {
+		symbol := protocol.DocumentSymbol{
+			Name:           table.Name,
+			Kind:           protocol.SymbolKindStruct,
+			Range:          spanRange(mapper, table.Position, table.End),
+			SelectionRange: mapper.toRange(table.Position),
+		}
+		for _, column := range table.Columns {
+			detail := column.Type
+			symbol.Children = append(symbol.Children, protocol.DocumentSymbol{
+				Name:           column.Name,
+				Detail:         &detail,
+				Kind:           protocol.SymbolKindField,
+				Range:          mapper.toRange(column.Position),
+				SelectionRange: mapper.toRange(column.Position),
+			})
+		}
+		outline = append(outline, symbol)
+	}
+
+	for _, note := range storage.Notes() {
+		// the first line of the content
+		detail, _, _ := strings.Cut(note.Content, "\n")
+		outline = append(outline, protocol.DocumentSymbol{
+			Name:           note.Name,
+			Detail:         &detail,
+			Kind:           protocol.SymbolKindString,
+			Range:          spanRange(mapper, note.Position, note.End),
+			SelectionRange: mapper.toRange(note.Position),
+		})
+	}
+	return outline, nil
+}
+
+// spanRange returns the client range from the start of from to the end of to
+func spanRange(mapper *lineMapper, from tokens.Position, to tokens.Position) protocol.Range {
+	endLine, endOffset := to.End()
+	return protocol.Range{
+		Start: mapper.toPosition(from.Line, from.Offset),
+		End:   mapper.toPosition(endLine, endOffset),
+	}
+}
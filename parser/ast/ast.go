@@ -0,0 +1,228 @@
+// Package ast declares the syntax tree produced by the parsers.
+// Nodes only describe the source, resolving names and references
+// is left to the binder building the symbols from the tree.
+package ast
+
+import (
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// Node is implemented by all nodes of the tree
+type Node interface {
+	Start() tokens.Position
+	End() tokens.Position
+}
+
+// Statement is a top-level declaration
+type Statement interface {
+	Node
+	statementNode()
+}
+
+// Span is the source range of a node from its first to its last item
+type Span struct {
+	From tokens.Position
+	To   tokens.Position
+}
+
+func (s Span) Start() tokens.Position { return s.From }
+func (s Span) End() tokens.Position   { return s.To }
+
+// File is a parsed document
+type File struct {
+	Span
+	Statements []Statement
+}
+
+// Ident is a single name
+type Ident struct {
+	Span
+	Value string
+}
+
+// Name is a name with optional scheme, e.g. auth.users
+type Name struct {
+	Span
+	Scheme *Ident
+	Name   *Ident
+}
+
+// SchemeValue returns the scheme or an empty string if not set
+func (n *Name) SchemeValue() string {
+	if n.Scheme == nil {
+		return ""
+	}
+	return n.Scheme.Value
+}
+
+func (n *Name) String() string {
+	if n.Scheme == nil {
+		return n.Name.Value
+	}
+	return n.Scheme.Value + "." + n.Name.Value
+}
+
+// Project is 'Project name { key: "value" }'
+type Project struct {
+	Span
+	Name    *Ident
+	Options []*Option
+	Note    *Note
+}
+
+type Option struct {
+	Span
+	Key   *Ident
+	Value string
+}
+
+// Table is 'Table scheme.name as alias [settings] { columns }'
+type Table struct {
+	Span
+	Name *Name
+	// Settings like 'headercolor: #3498DB'
+	Settings []*Setting
+	Alias    *Ident
+	Columns  []*Column
+	Indexes  []*Index
+	Note     *Note
+}
+
+// Column is 'name type [settings]'
+type Column struct {
+	Span
+	Name     *Ident
+	Type     *Ident
+	Settings []*Setting
+}
+
+// Setting is a flag like 'pk', a keyed setting like
+// 'note: "..."' or an inline ref, then Ref is set
+type Setting struct {
+	Span
+	Key   string
+	Value string
+	// Literal is the value of settings like 'default: 0'
+	Literal *Literal
+	Ref     *InlineRef
+}
+
+// Literal is a setting value like 0, 'text', #3498DB, true, null or `now()`
+type Literal struct {
+	Span
+	// Kind is tokens.NUMBER, STRING, COLOR, BOOLEAN, EXPRESSION or CONS_NULL
+	Kind  tokens.Token
+	Value string
+}
+
+// Source returns the literal as written in dbml
+func (l *Literal) Source() string {
+	switch l.Kind {
+	case tokens.STRING:
+		return "'" + strings.ReplaceAll(l.Value, "'", "\\'") + "'"
+	case tokens.EXPRESSION:
+		return "`" + l.Value + "`"
+	}
+	return l.Value
+}
+
+// InlineRef is the column setting 'ref: > table.column'
+type InlineRef struct {
+	Span
+	Type   string
+	Target *Endpoint
+}
+
+// Index is an entry of an indexes block, '(a, b) [unique]'
+type Index struct {
+	Span
+	Columns  []*Ident
+	Settings []*Setting
+}
+
+// Ref is 'Ref name: a.id > b.id' and its long form
+type Ref struct {
+	Span
+	Name  *Ident
+	Left  *Endpoint
+	Type  string
+	Right *Endpoint
+}
+
+// Endpoint is a side of a ref, 'scheme.table.column'
+type Endpoint struct {
+	Span
+	Table *Name
+	// Columns holds the column, composite
+	// endpoints like 'table.(a, b)' are rejected
+	Columns []*Ident
+}
+
+// Column returns the first column of the endpoint
+func (e *Endpoint) Column() string {
+	if len(e.Columns) == 0 {
+		return ""
+	}
+	return e.Columns[0].Value
+}
+
+// Enum is 'Enum scheme.name { values }'
+type Enum struct {
+	Span
+	Name   *Name
+	Values []*EnumValue
+}
+
+type EnumValue struct {
+	Span
+	Name     *Ident
+	Settings []*Setting
+}
+
+// TableGroup is 'TableGroup name [settings] { tables }'
+type TableGroup struct {
+	Span
+	Name     *Ident
+	Settings []*Setting
+	Members  []*Name
+}
+
+// Note is a sticky note 'Note name { "..." }' or,
+// without name, the note of a table or project
+type Note struct {
+	Span
+	Name  *Ident
+	Value string
+}
+
+// Use is 'use { table a as b } from "./a.dbml"', Reuse
+// is set for 'reuse', All for 'use * from'
+type Use struct {
+	Span
+	Reuse bool
+	All   bool
+	Items []*UseItem
+	Path  string
+}
+
+type UseItem struct {
+	Span
+	// Kind of the imported symbol like "table" or "enum"
+	Kind  string
+	Name  *Name
+	Alias *Ident
+}
+
+func (*Project) statementNode()    {}
+func (*Table) statementNode()      {}
+func (*Ref) statementNode()        {}
+func (*Enum) statementNode()       {}
+func (*TableGroup) statementNode() {}
+func (*Note) statementNode()       {}
+func (*Use) statementNode()        {}
+
+func (e *Endpoint) String() string {
+	return e.Table.String() + "." + e.Column()
+}
@@ -0,0 +1,146 @@
+package ast
+
+// Shifted returns a deep copy of statement moved by delta lines and bytes bytes
+func Shifted(statement Statement, delta int, bytes int) Statement {
+	s := shifter{lines: delta, bytes: bytes}
+	switch n := statement.(type) {
+	case *Project:
+		return s.project(n)
+	case *Table:
+		return s.table(n)
+	case *Ref:
+		return s.ref(n)
+	case *Enum:
+		return s.enum(n)
+	case *TableGroup:
+		return s.tableGroup(n)
+	case *Note:
+		return s.note(n)
+	case *Use:
+		return s.use(n)
+	}
+	return statement
+}
+
+// shifter copies nodes and moves them by lines and bytes
+type shifter struct {
+	lines int
+	bytes int
+}
+
+func (s shifter) span(span Span) Span {
+	return Span{From: span.From.Shifted(s.lines, s.bytes), To: span.To.Shifted(s.lines, s.bytes)}
+}
+
+func (s shifter) ident(n *Ident) *Ident {
+	if n == nil {
+		return nil
+	}
+	return &Ident{Span: s.span(n.Span), Value: n.Value}
+}
+
+func (s shifter) idents(nodes []*Ident) []*Ident {
+	if nodes == nil {
+		return nil
+	}
+	copied := make([]*Ident, 0, len(nodes))
+	for _, n := range nodes {
+		copied = append(copied, s.ident(n))
+	}
+	return copied
+}
+
+func (s shifter) name(n *Name) *Name {
+	if n == nil {
+		return nil
+	}
+	return &Name{Span: s.span(n.Span), Scheme: s.ident(n.Scheme), Name: s.ident(n.Name)}
+}
+
+func (s shifter) project(n *Project) *Project {
+	copied := &Project{Span: s.span(n.Span), Name: s.ident(n.Name), Note: s.note(n.Note)}
+	for _, option := range n.Options {
+		copied.Options = append(copied.Options, &Option{Span: s.span(option.Span), Key: s.ident(option.Key), Value: option.Value})
+	}
+	return copied
+}
+
+func (s shifter) table(n *Table) *Table {
+	copied := &Table{Span: s.span(n.Span), Name: s.name(n.Name), Settings: s.settings(n.Settings), Alias: s.ident(n.Alias), Note: s.note(n.Note)}
+	for _, column := range n.Columns {
+		copied.Columns = append(copied.Columns, &Column{
+			Span:     s.span(column.Span),
+			Name:     s.ident(column.Name),
+			Type:     s.ident(column.Type),
+			Settings: s.settings(column.Settings),
+		})
+	}
+	for _, index := range n.Indexes {
+		copied.Indexes = append(copied.Indexes, &Index{
+			Span:     s.span(index.Span),
+			Columns:  s.idents(index.Columns),
+			Settings: s.settings(index.Settings),
+		})
+	}
+	return copied
+}
+
+func (s shifter) settings(nodes []*Setting) []*Setting {
+	if nodes == nil {
+		return nil
+	}
+	copied := make([]*Setting, 0, len(nodes))
+	for _, n := range nodes {
+		setting := &Setting{Span: s.span(n.Span), Key: n.Key, Value: n.Value}
+		if n.Literal != nil {
+			setting.Literal = &Literal{Span: s.span(n.Literal.Span), Kind: n.Literal.Kind, Value: n.Literal.Value}
+		}
+		if n.Ref != nil {
+			setting.Ref = &InlineRef{Span: s.span(n.Ref.Span), Type: n.Ref.Type, Target: s.endpoint(n.Ref.Target)}
+		}
+		copied = append(copied, setting)
+	}
+	return copied
+}
+
+func (s shifter) ref(n *Ref) *Ref {
+	return &Ref{Span: s.span(n.Span), Name: s.ident(n.Name), Left: s.endpoint(n.Left), Type: n.Type, Right: s.endpoint(n.Right)}
+}
+
+func (s shifter) endpoint(n *Endpoint) *Endpoint {
+	if n == nil {
+		return nil
+	}
+	return &Endpoint{Span: s.span(n.Span), Table: s.name(n.Table), Columns: s.idents(n.Columns)}
+}
+
+func (s shifter) enum(n *Enum) *Enum {
+	copied := &Enum{Span: s.span(n.Span), Name: s.name(n.Name)}
+	for _, value := range n.Values {
+		copied.Values = append(copied.Values, &EnumValue{Span: s.span(value.Span), Name: s.ident(value.Name), Settings: s.settings(value.Settings)})
+	}
+	return copied
+}
+
+func (s shifter) tableGroup(n *TableGroup) *TableGroup {
+	copied := &TableGroup{Span: s.span(n.Span), Name: s.ident(n.Name), Settings: s.settings(n.Settings)}
+	for _, member := range n.Members {
+		copied.Members = append(copied.Members, s.name(member))
+	}
+	return copied
+}
+
+func (s shifter) note(n *Note) *Note {
+	if n == nil {
+		return nil
+	}
+	return &Note{Span: s.span(n.Span), Name: s.ident(n.Name), Value: n.Value}
+}
+
+func (s shifter) use(n *Use) *Use {
+	copied := &Use{Span: s.span(n.Span), Reuse: n.Reuse, All: n.All, Path: n.Path}
+	for _, item := range n.Items {
+		copied.Items = append(copied.Items, &UseItem{Span: s.span(item.Span), Kind: item.Kind, Name: s.name(item.Name), Alias: s.ident(item.Alias)})
+	}
+	return copied
+}
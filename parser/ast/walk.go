@@ -0,0 +1,127 @@
+package ast
+
+// Visitor is called for every node by Walk. If Visit returns
+// a non-nil visitor, it is used to walk the children of node.
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node in depth-first order
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *File:
+		for _, statement := range n.Statements {
+			Walk(v, statement)
+		}
+	case *Name:
+		walkIdent(v, n.Scheme)
+		walkIdent(v, n.Name)
+	case *Project:
+		walkIdent(v, n.Name)
+		for _, option := range n.Options {
+			Walk(v, option)
+		}
+		if n.Note != nil {
+			Walk(v, n.Note)
+		}
+	case *Option:
+		walkIdent(v, n.Key)
+	case *Table:
+		Walk(v, n.Name)
+		walkIdent(v, n.Alias)
+		walkSettings(v, n.Settings)
+		for _, column := range n.Columns {
+			Walk(v, column)
+		}
+		for _, index := range n.Indexes {
+			Walk(v, index)
+		}
+		if n.Note != nil {
+			Walk(v, n.Note)
+		}
+	case *Column:
+		walkIdent(v, n.Name)
+		walkIdent(v, n.Type)
+		walkSettings(v, n.Settings)
+	case *Setting:
+		if n.Literal != nil {
+			Walk(v, n.Literal)
+		}
+		if n.Ref != nil {
+			Walk(v, n.Ref)
+		}
+	case *InlineRef:
+		Walk(v, n.Target)
+	case *Index:
+		for _, column := range n.Columns {
+			Walk(v, column)
+		}
+		walkSettings(v, n.Settings)
+	case *Ref:
+		walkIdent(v, n.Name)
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *Endpoint:
+		Walk(v, n.Table)
+		for _, column := range n.Columns {
+			Walk(v, column)
+		}
+	case *Enum:
+		Walk(v, n.Name)
+		for _, value := range n.Values {
+			Walk(v, value)
+		}
+	case *EnumValue:
+		walkIdent(v, n.Name)
+		walkSettings(v, n.Settings)
+	case *TableGroup:
+		walkIdent(v, n.Name)
+		walkSettings(v, n.Settings)
+		for _, member := range n.Members {
+			Walk(v, member)
+		}
+	case *Note:
+		walkIdent(v, n.Name)
+	case *Use:
+		for _, item := range n.Items {
+			Walk(v, item)
+		}
+	case *UseItem:
+		Walk(v, n.Name)
+		walkIdent(v, n.Alias)
+	}
+
+	v.Visit(nil)
+}
+
+// walkIdent walks optional identifiers
+func walkIdent(v Visitor, ident *Ident) {
+	if ident != nil {
+		Walk(v, ident)
+	}
+}
+
+func walkSettings(v Visitor, settings []*Setting) {
+	for _, setting := range settings {
+		Walk(v, setting)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if node != nil && f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect calls f for every node of the tree rooted at node,
+// the children of a node are skipped if f returns false
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,272 @@
+// Package binder builds the symbols of a document from its syntax tree.
+package binder
+
+import (
+	"slices"
+
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// Bind declares all statements of file in a new storage
+func Bind(file *ast.File) (*symbols.Storage, error) {
+	storage := symbols.NewStorage()
+	for _, statement := range file.Statements {
+		if err := Declare(storage, statement); err != nil {
+			return storage, err
+		}
+	}
+	return storage, nil
+}
+
+// Declare adds the symbols of statement to storage. Statements
+// are not modified, they can be declared into several storages.
+func Declare(storage *symbols.Storage, statement ast.Statement) error {
+	return DeclareSymbol(storage, BindStatement(statement))
+}
+
+// BindStatement returns the symbol of a top-level statement, e.g. the
+// *symbols.Table of a table, or nil for statements without symbol
+func BindStatement(statement ast.Statement) any {
+	switch statement := statement.(type) {
+	case *ast.Project:
+		return bindProject(statement)
+	case *ast.Table:
+		return bindTable(statement)
+	case *ast.Ref:
+		return bindRef(statement)
+	case *ast.Use:
+		return bindUse(statement)
+	case *ast.Note:
+		return bindNote(statement)
+	case *ast.Enum:
+		return bindEnum(statement)
+	case *ast.TableGroup:
+		return bindTableGroup(statement)
+	}
+	return nil
+}
+
+// DeclareSymbol adds a symbol returned by BindStatement to storage.
+// The symbol is not modified, so it can be declared into several
+// storages without binding the statement again.
+func DeclareSymbol(storage *symbols.Storage, symbol any) error {
+	switch symbol := symbol.(type) {
+	case *symbols.Project:
+		storage.AddProject(symbol)
+
+	case *symbols.Table:
+		// refs declared later are added to the references of the copy
+		table := *symbol
+		table.References = slices.Clip(table.References)
+		storage.PutTable(&table)
+
+	case *symbols.Relationship:
+		table, exists := storage.ResolveTable(symbol.SchemeA, symbol.TableA)
+		if !exists {
+			// host table might be imported from another file
+			storage.AddRelationship(symbol)
+			return nil
+		}
+		table.References = append(table.References, symbol)
+		return storage.UpdateTable(table.QualifiedName(), table)
+
+	case *symbols.Import:
+		storage.AddImport(symbol)
+
+	case *symbols.Note:
+		storage.AddNote(symbol)
+
+	case *symbols.Enum:
+		storage.AddEnum(symbol)
+
+	case *symbols.TableGroup:
+		storage.AddTableGroup(symbol)
+	}
+	return nil
+}
+
+func bindProject(node *ast.Project) *symbols.Project {
+	project := &symbols.Project{
+		Options:         make(map[string]string),
+		OptionPositions: make(map[string]tokens.Position),
+		Position:        node.From,
+	}
+	if node.Name != nil {
+		project.Name = node.Name.Value
+	}
+	for _, option := range node.Options {
+		project.Options[option.Key.Value] = option.Value
+		project.OptionPositions[option.Key.Value] = option.Key.From
+	}
+	if node.Note != nil {
+		project.Note = node.Note.Value
+	}
+	return project
+}
+
+func bindNote(node *ast.Note) *symbols.Note {
+	note := &symbols.Note{
+		Content:  node.Value,
+		Position: node.From,
+		End:      node.To,
+	}
+	if node.Name != nil {
+		note.Name = node.Name.Value
+	}
+	return note
+}
+
+func bindTable(node *ast.Table) *symbols.Table {
+	table := &symbols.Table{
+		Scheme:   node.Name.SchemeValue(),
+		Name:     node.Name.Name.Value,
+		Position: node.From,
+		End:      node.To,
+	}
+	if node.Alias != nil {
+		table.Alias = node.Alias.Value
+	}
+	if node.Note != nil {
+		table.Note = node.Note.Value
+	}
+	for _, setting := range node.Settings {
+		switch {
+		case setting.Key == "headercolor":
+			table.HeaderColor = setting.Value
+		case setting.Key == "note" && table.Note == "":
+			table.Note = setting.Value
+		}
+	}
+
+	for _, columnNode := range node.Columns {
+		column := &symbols.Column{
+			Name:     columnNode.Name.Value,
+			Position: columnNode.Name.From,
+		}
+		if columnNode.Type != nil {
+			column.Type = columnNode.Type.Value
+		}
+		for _, setting := range columnNode.Settings {
+			if setting.Ref == nil {
+				column.Constraints = append(column.Constraints, &symbols.Constraint{Key: setting.Key, Value: setting.Value})
+				continue
+			}
+			table.References = append(table.References, &symbols.Relationship{
+				SchemeA:  table.Scheme,
+				TableA:   table.Name,
+				ColumnA:  column.Name,
+				SchemeB:  setting.Ref.Target.Table.SchemeValue(),
+				TableB:   setting.Ref.Target.Table.Name.Value,
+				ColumnB:  setting.Ref.Target.Column(),
+				Type:     setting.Ref.Type,
+				Inline:   true,
+				Position: setting.From,
+			})
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	for _, indexNode := range node.Indexes {
+		table.Indexes = append(table.Indexes, bindIndex(indexNode))
+	}
+	return table
+}
+
+func bindIndex(node *ast.Index) *symbols.Index {
+	index := &symbols.Index{Position: node.From}
+	for _, column := range node.Columns {
+		index.Columns = append(index.Columns, column.Value)
+	}
+	for _, setting := range node.Settings {
+		switch {
+		case setting.Key == "" && setting.Value == "pk":
+			index.PrimaryKey = true
+		case setting.Key == "" && setting.Value == "unique":
+			index.Unique = true
+		case setting.Key == "name":
+			index.Name = setting.Value
+		case setting.Key == "type":
+			index.Type = setting.Value
+		case setting.Key == "note":
+			index.Note = setting.Value
+		}
+	}
+	return index
+}
+
+func bindEnum(node *ast.Enum) *symbols.Enum {
+	enum := &symbols.Enum{
+		Scheme:   node.Name.SchemeValue(),
+		Name:     node.Name.Name.Value,
+		Position: node.From,
+		End:      node.To,
+	}
+	for _, valueNode := range node.Values {
+		value := &symbols.EnumValue{Name: valueNode.Name.Value}
+		for _, setting := range valueNode.Settings {
+			if setting.Key == "note" {
+				value.Note = setting.Value
+			}
+		}
+		enum.Values = append(enum.Values, value)
+	}
+	return enum
+}
+
+func bindTableGroup(node *ast.TableGroup) *symbols.TableGroup {
+	group := &symbols.TableGroup{
+		Name:     node.Name.Value,
+		Position: node.From,
+		End:      node.To,
+	}
+	for _, setting := range node.Settings {
+		if setting.Key == "color" {
+			group.Color = setting.Value
+		}
+	}
+	for _, member := range node.Members {
+		group.Tables = append(group.Tables, symbols.QualifiedName(member.SchemeValue(), member.Name.Value))
+	}
+	return group
+}
+
+func bindRef(node *ast.Ref) *symbols.Relationship {
+	rel := &symbols.Relationship{
+		SchemeA:  node.Left.Table.SchemeValue(),
+		TableA:   node.Left.Table.Name.Value,
+		ColumnA:  node.Left.Column(),
+		SchemeB:  node.Right.Table.SchemeValue(),
+		TableB:   node.Right.Table.Name.Value,
+		ColumnB:  node.Right.Column(),
+		Type:     node.Type,
+		Position: node.From,
+	}
+	if node.Name != nil {
+		rel.Name = node.Name.Value
+	}
+	return rel
+}
+
+func bindUse(node *ast.Use) *symbols.Import {
+	imp := &symbols.Import{
+		Reuse:    node.Reuse,
+		All:      node.All,
+		Items:    make([]*symbols.ImportItem, 0, len(node.Items)),
+		Path:     node.Path,
+		Position: node.From,
+	}
+	for _, item := range node.Items {
+		importItem := &symbols.ImportItem{
+			Kind:   item.Kind,
+			Scheme: item.Name.SchemeValue(),
+			Name:   item.Name.Name.Value,
+		}
+		if item.Alias != nil {
+			importItem.Alias = item.Alias.Value
+		}
+		imp.Items = append(imp.Items, importItem)
+	}
+	return imp
+}
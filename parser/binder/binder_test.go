@@ -0,0 +1,57 @@
+package binder_test
+
+import (
+	"testing"
+
+	"github.com/h0rzn/dbml-lsp/parser"
+	explicitparser "github.com/h0rzn/dbml-lsp/parser/explicit_parser"
+)
+
+func TestRefBeforeHostTable(t *testing.T) {
+	text := "Ref: posts.user_id > users.id\n\n" +
+		"Table users {\n  id integer [pk]\n}\n\n" +
+		"Table posts {\n  id integer [pk]\n  user_id integer\n}\n\n" +
+		"Ref: comments.post_id > posts.id\n"
+	result, err := parser.NewParser(explicitparser.NewParser()).ParseText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	posts, exists := result.Symbols.TableByName("posts")
+	if !exists {
+		t.Fatal("table posts is not declared")
+	}
+	if len(posts.References) != 1 || posts.References[0].ColumnA != "user_id" {
+		t.Errorf("ref declared before posts is not attached to it: %v", posts.References)
+	}
+	// comments is not declared, e.g. imported from another file
+	unattached := result.Symbols.Relationships()
+	if len(unattached) != 1 || unattached[0].TableA != "comments" {
+		t.Errorf("expected the comments ref to stay unattached, got %v", unattached)
+	}
+}
+
+func TestLongFormRef(t *testing.T) {
+	text := "Ref user {\n  posts.user_id > users.id\n}\n\n" +
+		"Table users {\n  id integer [pk]\n}\n\n" +
+		"Table posts {\n  id integer [pk]\n  user_id integer\n}\n"
+	result, err := parser.NewParser(explicitparser.NewParser()).ParseText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics %v", result.Diagnostics)
+	}
+	posts, exists := result.Symbols.TableByName("posts")
+	if !exists {
+		t.Fatal("table posts after the long form ref is not declared")
+	}
+	if len(posts.References) != 1 || posts.References[0].Name != "user" {
+		t.Errorf("long form ref is not attached to posts: %v", posts.References)
+	}
+
+	composite := "Ref: posts.(id, user_id) > users.(id, name)\n"
+	if _, err := parser.NewParser(explicitparser.NewParser()).ParseText(composite); err == nil {
+		t.Error("expected an error for the composite ref")
+	}
+}
@@ -0,0 +1,179 @@
+// Package cst provides a lossless concrete syntax tree of dbml documents.
+// Every byte of the input, whitespace and comments included, belongs
+// to a token of the tree, so the text of the root equals the input
+// and edits can be limited to the nodes they change.
+package cst
+
+import (
+	"sort"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// Tree is the syntax tree of a document
+type Tree struct {
+	Root *Node
+	text string
+	// lines holds the byte offset of every line start
+	lines []int
+}
+
+// Parse builds the tree of text, it never fails.
+// Unknown input is kept in Error nodes.
+func Parse(text string) *Tree {
+	tree := &Tree{
+		Root:  &Node{green: parse(text)},
+		text:  text,
+		lines: []int{0},
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			tree.lines = append(tree.lines, i+1)
+		}
+	}
+	return tree
+}
+
+func (t *Tree) Text() string {
+	return t.text
+}
+
+func (t *Tree) Tables() []TableNode {
+	tables := make([]TableNode, 0)
+	for _, node := range t.Root.ChildrenOf(Table) {
+		tables = append(tables, TableNode{node})
+	}
+	return tables
+}
+
+func (t *Tree) Refs() []RefNode {
+	refs := make([]RefNode, 0)
+	for _, node := range t.Root.ChildrenOf(Ref) {
+		refs = append(refs, RefNode{node})
+	}
+	return refs
+}
+
+func (t *Tree) Enums() []EnumNode {
+	enums := make([]EnumNode, 0)
+	for _, node := range t.Root.ChildrenOf(Enum) {
+		enums = append(enums, EnumNode{node})
+	}
+	return enums
+}
+
+func (t *Tree) TableGroups() []TableGroupNode {
+	groups := make([]TableGroupNode, 0)
+	for _, node := range t.Root.ChildrenOf(TableGroup) {
+		groups = append(groups, TableGroupNode{node})
+	}
+	return groups
+}
+
+func (t *Tree) Notes() []NoteNode {
+	notes := make([]NoteNode, 0)
+	for _, node := range t.Root.ChildrenOf(Note) {
+		notes = append(notes, NoteNode{node})
+	}
+	return notes
+}
+
+func (t *Tree) Project() (ProjectNode, bool) {
+	node := t.Root.Child(Project)
+	return ProjectNode{node}, node != nil
+}
+
+// Table returns the table declared as name
+func (t *Tree) Table(name string) (TableNode, bool) {
+	for _, table := range t.Tables() {
+		if table.Name() != nil && table.Name().Value() == name {
+			return table, true
+		}
+	}
+	return TableNode{}, false
+}
+
+// ColumnAt returns the column declared in line
+func (t *Tree) ColumnAt(line uint32) (ColumnNode, bool) {
+	for _, table := range t.Tables() {
+		for _, column := range table.Columns() {
+			start, _ := column.Significant()
+			if t.Position(start).Line == line {
+				return column, true
+			}
+		}
+	}
+	return ColumnNode{}, false
+}
+
+// Source returns the text of node without surrounding trivia
+func (t *Tree) Source(node *Node) string {
+	start, end := node.Significant()
+	return t.text[start:end]
+}
+
+// Offset converts a position to a byte offset
+func (t *Tree) Offset(line uint32, offset uint32) int {
+	if int(line) >= len(t.lines) {
+		return len(t.text)
+	}
+	start := t.lines[line]
+	var units uint32
+	for i, char := range t.text[start:] {
+		if units >= offset || char == '\n' {
+			return start + i
+		}
+		units += tokens.UTF16RuneLen(char)
+	}
+	return len(t.text)
+}
+
+// Position converts a byte offset to a position
+func (t *Tree) Position(offset int) tokens.Position {
+	line := sort.Search(len(t.lines), func(i int) bool { return t.lines[i] > offset }) - 1
+	return tokens.Position{
+		Line:   uint32(line),
+		Offset: tokens.UTF16Len(t.text[t.lines[line]:offset]),
+		Byte:   uint32(offset),
+	}
+}
+
+// NodeAt returns the innermost node at the byte offset
+func (t *Tree) NodeAt(offset int) *Node {
+	node := t.Root
+	for {
+		var next *Node
+		for _, child := range node.Children() {
+			if !child.IsToken() && child.Offset() <= offset && offset < child.End() {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+}
+
+// Replace returns the edit replacing the text of node,
+// trivia surrounding the node is kept
+func (t *Tree) Replace(node *Node, text string) analysis.TextEdit {
+	start, end := node.Significant()
+	return t.ReplaceRange(start, end, text)
+}
+
+// ReplaceRange returns the edit replacing the byte range
+func (t *Tree) ReplaceRange(start int, end int, text string) analysis.TextEdit {
+	return analysis.TextEdit{
+		Start:   t.Position(start),
+		End:     t.Position(end),
+		NewText: text,
+	}
+}
+
+// Insert returns the edit inserting text at the byte offset
+func (t *Tree) Insert(offset int, text string) analysis.TextEdit {
+	return t.ReplaceRange(offset, offset, text)
+}
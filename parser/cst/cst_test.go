@@ -0,0 +1,106 @@
+package cst_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/h0rzn/dbml-lsp/parser"
+	"github.com/h0rzn/dbml-lsp/parser/cst"
+	explicitparser "github.com/h0rzn/dbml-lsp/parser/explicit_parser"
+)
+
+// malformed are inputs the explicit parser rejects,
+// the tree has to keep every byte of them anyway
+var malformed = []string{
+	"",
+	"}",
+	"{{{",
+	"Table",
+	"Table users {",
+	"Table users {\n  id integer [pk\n",
+	"Table users {\n  id integer [note: 'unterminated]\n}\n",
+	"Table users {\n  id integer [note: '''\n  multi\n",
+	"Table users {\n  id `now(\n}\n",
+	"Table \"order items {\n}\n",
+	"Ref: a.id >\n",
+	"Ref {\n  a.id > b.id\n",
+	"Ref: a.(id, b) > c.(id, d)\n",
+	"Enum status {\n  'active\n}\n",
+	"TableGroup g { a b\n",
+	"// comment without line break",
+	"/* unterminated block comment",
+	"Table users {\n  id integer\n}\n}\n}\n",
+	"Project p { database_type: }\n",
+	"\x00\xff\xfe invalid utf-8 \n",
+	"Table t {\r\n  id int\r\n}\r\n",
+}
+
+// assertLossless checks that the tree covers text byte for byte
+func assertLossless(t *testing.T, name string, text string) {
+	t.Helper()
+	tree := cst.Parse(text)
+	if tree.Root.Text() != text {
+		t.Errorf("%s: text of the root differs from the input\n%q\ngot\n%q", name, text, tree.Root.Text())
+	}
+	if tree.Root.Offset() != 0 || tree.Root.End() != len(text) {
+		t.Errorf("%s: root spans %d to %d, expected 0 to %d", name, tree.Root.Offset(), tree.Root.End(), len(text))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("..", "..", "export", "testdata", "*.dbml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in export/testdata")
+	}
+	for _, fixture := range fixtures {
+		text, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertLossless(t, fixture, string(text))
+		// every prefix is a half typed document
+		for end := range len(text) {
+			assertLossless(t, fixture+" prefix", string(text[:end]))
+		}
+	}
+	for _, text := range malformed {
+		assertLossless(t, "malformed", text)
+	}
+}
+
+// TestTables checks that both grammars read the same tables
+func TestTables(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("..", "..", "export", "testdata", "*.dbml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fixture := range fixtures {
+		text, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := parser.NewParser(explicitparser.NewParser()).ParseText(string(text))
+		if err != nil {
+			t.Fatalf("%s: %s", fixture, err)
+		}
+		tree := cst.Parse(string(text))
+		for _, table := range result.Symbols.Tables() {
+			name := table.Name
+			if table.Scheme != "" {
+				name = table.Scheme + "." + table.Name
+			}
+			node, exists := tree.Table(name)
+			if !exists {
+				t.Errorf("%s: table %s is missing in the tree", fixture, name)
+				continue
+			}
+			if columns := node.Columns(); len(columns) != len(table.Columns) {
+				t.Errorf("%s: table %s has %d columns in the tree, expected %d", fixture, name, len(columns), len(table.Columns))
+			}
+		}
+	}
+}
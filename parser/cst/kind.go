@@ -0,0 +1,125 @@
+package cst
+
+// Kind is the kind of a token or node in the syntax tree
+type Kind int
+
+// token kinds
+const (
+	Whitespace Kind = iota
+	Newline
+	LineComment
+	BlockComment
+	Ident
+	Number
+	// String is any quoted literal: 'a', "a", '''a''' and `a`
+	String
+	BraceOpen
+	BraceClose
+	BracketOpen
+	BracketClose
+	ParenOpen
+	ParenClose
+	Colon
+	Comma
+	Dot
+	Less
+	Greater
+	Minus
+	LessGreater
+	Asterisk
+	// Unknown is a character not used by dbml
+	Unknown
+)
+
+// node kinds
+const (
+	File Kind = iota + 100
+	Project
+	Table
+	TableGroup
+	Enum
+	Ref
+	Note
+	Use
+	Column
+	ColumnType
+	EnumValue
+	Indexes
+	Index
+	// Body is a braced block
+	Body
+	Name
+	Alias
+	Settings
+	Setting
+	Option
+	Endpoint
+	Operator
+	// Error holds input that could not be parsed
+	Error
+)
+
+var kindNames = map[Kind]string{
+	Whitespace:   "Whitespace",
+	Newline:      "Newline",
+	LineComment:  "LineComment",
+	BlockComment: "BlockComment",
+	Ident:        "Ident",
+	Number:       "Number",
+	String:       "String",
+	BraceOpen:    "BraceOpen",
+	BraceClose:   "BraceClose",
+	BracketOpen:  "BracketOpen",
+	BracketClose: "BracketClose",
+	ParenOpen:    "ParenOpen",
+	ParenClose:   "ParenClose",
+	Colon:        "Colon",
+	Comma:        "Comma",
+	Dot:          "Dot",
+	Less:         "Less",
+	Greater:      "Greater",
+	Minus:        "Minus",
+	LessGreater:  "LessGreater",
+	Asterisk:     "Asterisk",
+	Unknown:      "Unknown",
+	File:         "File",
+	Project:      "Project",
+	Table:        "Table",
+	TableGroup:   "TableGroup",
+	Enum:         "Enum",
+	Ref:          "Ref",
+	Note:         "Note",
+	Use:          "Use",
+	Column:       "Column",
+	ColumnType:   "ColumnType",
+	EnumValue:    "EnumValue",
+	Indexes:      "Indexes",
+	Index:        "Index",
+	Body:         "Body",
+	Name:         "Name",
+	Alias:        "Alias",
+	Settings:     "Settings",
+	Setting:      "Setting",
+	Option:       "Option",
+	Endpoint:     "Endpoint",
+	Operator:     "Operator",
+	Error:        "Error",
+}
+
+func (k Kind) String() string {
+	if name, exists := kindNames[k]; exists {
+		return name
+	}
+	return "Kind(?)"
+}
+
+// IsToken reports whether k is a token kind
+func (k Kind) IsToken() bool {
+	return k < File
+}
+
+// IsTrivia reports whether k carries no meaning:
+// whitespace, line breaks and comments
+func (k Kind) IsTrivia() bool {
+	return k == Whitespace || k == Newline || k == LineComment || k == BlockComment
+}
@@ -0,0 +1,108 @@
+package cst
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lexToken is a token of the input, every byte
+// of the input belongs to exactly one token
+type lexToken struct {
+	kind Kind
+	text string
+}
+
+var punctuation = map[byte]Kind{
+	'{': BraceOpen,
+	'}': BraceClose,
+	'[': BracketOpen,
+	']': BracketClose,
+	'(': ParenOpen,
+	')': ParenClose,
+	':': Colon,
+	',': Comma,
+	'.': Dot,
+	'<': Less,
+	'>': Greater,
+	'-': Minus,
+	'*': Asterisk,
+}
+
+func lex(text string) []lexToken {
+	items := make([]lexToken, 0, len(text)/4)
+	for len(text) > 0 {
+		kind, size := next(text)
+		items = append(items, lexToken{kind: kind, text: text[:size]})
+		text = text[size:]
+	}
+	return items
+}
+
+// next returns kind and byte length of the token text starts with
+func next(text string) (Kind, int) {
+	char, size := utf8.DecodeRuneInString(text)
+	switch {
+	case char == '\n':
+		return Newline, 1
+	case char == '\r' && strings.HasPrefix(text, "\r\n"):
+		return Newline, 2
+	case unicode.IsSpace(char):
+		return Whitespace, span(text, func(r rune) bool { return r != '\n' && unicode.IsSpace(r) })
+	case strings.HasPrefix(text, "//"):
+		if end := strings.IndexByte(text, '\n'); end >= 0 {
+			if end > 0 && text[end-1] == '\r' {
+				end--
+			}
+			return LineComment, end
+		}
+		return LineComment, len(text)
+	case strings.HasPrefix(text, "/*"):
+		if end := strings.Index(text[2:], "*/"); end >= 0 {
+			return BlockComment, end + 4
+		}
+		return BlockComment, len(text)
+	case strings.HasPrefix(text, "'''"):
+		return String, quoted(text, "'''")
+	case char == '\'' || char == '"' || char == '`':
+		return String, quoted(text, string(char))
+	case char == '<' && strings.HasPrefix(text, "<>"):
+		return LessGreater, 2
+	case char == '_' || unicode.IsLetter(char):
+		return Ident, span(text, func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) })
+	case unicode.IsDigit(char):
+		return Number, span(text, func(r rune) bool { return r == '.' || unicode.IsDigit(r) })
+	}
+	if kind, exists := punctuation[text[0]]; exists {
+		return kind, 1
+	}
+	return Unknown, size
+}
+
+// span returns the byte length of the prefix matching accept
+func span(text string, accept func(rune) bool) int {
+	for i, char := range text {
+		if !accept(char) {
+			return i
+		}
+	}
+	return len(text)
+}
+
+// quoted returns the byte length of the literal enclosed by quote,
+// backslash escapes are skipped. Unterminated literals end at the
+// line break, multi line literals at the end of input.
+func quoted(text string, quote string) int {
+	multiline := quote == "'''"
+	for i := len(quote); i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case strings.HasPrefix(text[i:], quote):
+			return i + len(quote)
+		case text[i] == '\n' && !multiline:
+			return i
+		}
+	}
+	return len(text)
+}
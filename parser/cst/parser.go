@@ -0,0 +1,431 @@
+package cst
+
+import "strings"
+
+// parser builds the green tree from the token stream.
+// It never fails: input it does not understand ends up in
+// Error nodes, so every token of the input is part of the tree.
+type parser struct {
+	tokens []lexToken
+	pos    int
+	stack  []*frame
+}
+
+type frame struct {
+	kind     Kind
+	children []*green
+}
+
+func parse(text string) *green {
+	p := &parser{tokens: lex(text)}
+	p.start(File)
+	for !p.eof() {
+		if p.at(p.trivia) {
+			p.bump()
+			continue
+		}
+		p.statement()
+	}
+	return p.finish()
+}
+
+func (p *parser) statement() {
+	switch {
+	case p.atKeyword("project"):
+		p.definition(Project, p.projectLine)
+	case p.atKeyword("table"):
+		p.definition(Table, p.tableLine)
+	case p.atKeyword("tablegroup"):
+		p.definition(TableGroup, p.tableGroupLine)
+	case p.atKeyword("enum"):
+		p.definition(Enum, p.enumLine)
+	case p.atKeyword("note"):
+		p.definition(Note, p.noteLine)
+	case p.atKeyword("ref"):
+		p.ref()
+	case p.atKeyword("use", "reuse"):
+		p.start(Use)
+		p.bumpUntil(isKind(Newline))
+		p.finish()
+	default:
+		p.errorUntil(isKind(Newline))
+	}
+}
+
+// definition parses 'Keyword name [as alias] [settings] { ... }'
+func (p *parser) definition(kind Kind, line func()) {
+	p.start(kind)
+	p.bump()
+	p.inlineTrivia()
+	if p.at(isKind(Ident, String)) {
+		p.name()
+		p.inlineTrivia()
+	}
+	if p.atKeyword("as") {
+		p.start(Alias)
+		p.bump()
+		p.inlineTrivia()
+		if p.at(isKind(Ident, String)) {
+			p.name()
+		}
+		p.finish()
+		p.inlineTrivia()
+	}
+	if p.at(isKind(BracketOpen)) {
+		p.settings()
+		p.inlineTrivia()
+	}
+	if p.at(isKind(BraceOpen)) {
+		p.body(line)
+	}
+	p.finish()
+}
+
+// body parses a braced block, line parses everything
+// between the line breaks of the block
+func (p *parser) body(line func()) {
+	p.start(Body)
+	p.bump()
+	for !p.eof() {
+		if p.at(isKind(BraceClose)) {
+			p.bump()
+			break
+		}
+		if p.at(p.trivia) {
+			p.bump()
+			continue
+		}
+		start := p.pos
+		line()
+		if p.pos == start {
+			p.errorUntil(isLineEnd)
+		}
+	}
+	p.finish()
+}
+
+func (p *parser) tableLine() {
+	switch {
+	case p.atKeyword("note") && p.nextIs(Colon, BraceOpen):
+		p.note()
+	case p.atKeyword("indexes") && p.nextIs(BraceOpen):
+		p.start(Indexes)
+		p.bump()
+		p.inlineTrivia()
+		p.body(p.indexLine)
+		p.finish()
+	case p.at(isKind(Ident, String)):
+		p.column()
+	}
+}
+
+// column parses 'name type [settings]'
+func (p *parser) column() {
+	p.start(Column)
+	p.name()
+	p.inlineTrivia()
+	if !p.at(isKind(BracketOpen)) && !p.at(isLineEnd) {
+		p.start(ColumnType)
+		p.bumpUntil(isKind(Newline, BracketOpen, BraceClose))
+		p.finish()
+		p.inlineTrivia()
+	}
+	if p.at(isKind(BracketOpen)) {
+		p.settings()
+	}
+	p.rest()
+	p.finish()
+}
+
+func (p *parser) indexLine() {
+	p.start(Index)
+	if !p.at(isKind(BracketOpen)) {
+		p.bumpUntil(isKind(Newline, BracketOpen, BraceClose))
+		p.inlineTrivia()
+	}
+	if p.at(isKind(BracketOpen)) {
+		p.settings()
+	}
+	p.rest()
+	p.finish()
+}
+
+func (p *parser) enumLine() {
+	if !p.at(isKind(Ident, String)) {
+		return
+	}
+	p.start(EnumValue)
+	p.name()
+	p.inlineTrivia()
+	if p.at(isKind(BracketOpen)) {
+		p.settings()
+	}
+	p.rest()
+	p.finish()
+}
+
+func (p *parser) tableGroupLine() {
+	if p.at(isKind(Ident, String)) {
+		p.name()
+	}
+}
+
+func (p *parser) projectLine() {
+	if p.atKeyword("note") && p.nextIs(Colon, BraceOpen) {
+		p.note()
+		return
+	}
+	if !p.at(isKind(Ident)) {
+		return
+	}
+	p.start(Option)
+	p.bump()
+	p.inlineTrivia()
+	if p.at(isKind(Colon)) {
+		p.bump()
+		p.inlineTrivia()
+	}
+	p.bumpUntil(isLineEnd)
+	p.finish()
+}
+
+func (p *parser) noteLine() {
+	p.bumpUntil(isLineEnd)
+}
+
+// note parses 'Note: value' and 'Note { value }' inside definitions
+func (p *parser) note() {
+	p.start(Note)
+	p.bump()
+	p.inlineTrivia()
+	if p.at(isKind(BraceOpen)) {
+		p.body(p.noteLine)
+	} else {
+		p.bump()
+		p.inlineTrivia()
+		p.bumpUntil(isLineEnd)
+	}
+	p.finish()
+}
+
+// ref parses 'Ref [name]: a > b [settings]' and 'Ref [name] { a > b }'
+func (p *parser) ref() {
+	p.start(Ref)
+	p.bump()
+	p.inlineTrivia()
+	if p.at(isKind(Ident, String)) {
+		p.name()
+		p.inlineTrivia()
+	}
+	if p.at(isKind(Colon)) {
+		p.bump()
+		p.inlineTrivia()
+		p.relation()
+	} else if p.at(isKind(BraceOpen)) {
+		p.body(p.relation)
+	}
+	p.finish()
+}
+
+func (p *parser) relation() {
+	p.endpoint()
+	p.inlineTrivia()
+	if p.at(isKind(Less, Greater, Minus, LessGreater)) {
+		p.start(Operator)
+		p.bump()
+		p.finish()
+		p.inlineTrivia()
+	}
+	p.endpoint()
+	p.inlineTrivia()
+	if p.at(isKind(BracketOpen)) {
+		p.settings()
+	}
+	p.rest()
+}
+
+// endpoint parses 'scheme.table.column' and 'table.(a, b)'
+func (p *parser) endpoint() {
+	if !p.at(isKind(Ident, String)) {
+		return
+	}
+	p.start(Endpoint)
+	p.bump()
+	for p.at(isKind(Dot)) {
+		p.bump()
+		if p.at(isKind(ParenOpen)) {
+			p.bump()
+			p.bumpUntil(isKind(ParenClose, Newline))
+			if p.at(isKind(ParenClose)) {
+				p.bump()
+			}
+			break
+		}
+		if p.at(isKind(Ident, String)) {
+			p.bump()
+		}
+	}
+	p.finish()
+}
+
+// name parses dotted names like 'scheme.table'
+func (p *parser) name() {
+	p.start(Name)
+	p.bump()
+	for p.at(isKind(Dot)) {
+		p.bump()
+		if p.at(isKind(Ident, String)) {
+			p.bump()
+		}
+	}
+	p.finish()
+}
+
+// settings parses '[a, b: c]', settings may span several lines
+func (p *parser) settings() {
+	p.start(Settings)
+	p.bump()
+	for !p.eof() {
+		switch {
+		case p.at(isKind(BracketClose)):
+			p.bump()
+			p.finish()
+			return
+		case p.at(isKind(BraceClose)):
+			// unterminated, leave the brace to the body
+			p.finish()
+			return
+		case p.at(p.trivia), p.at(isKind(Comma)):
+			p.bump()
+		default:
+			p.start(Setting)
+			p.bumpUntil(isKind(Comma, BracketClose, BraceClose, Newline))
+			p.finish()
+		}
+	}
+	p.finish()
+}
+
+// rest puts everything up to the line end into an Error node
+func (p *parser) rest() {
+	p.inlineTrivia()
+	if !p.at(isLineEnd) && !p.eof() {
+		p.errorUntil(isLineEnd)
+	}
+}
+
+func (p *parser) errorUntil(stop func(Kind) bool) {
+	p.start(Error)
+	start := p.pos
+	p.bumpUntil(stop)
+	if p.pos == start && !p.eof() {
+		p.bump()
+	}
+	p.finish()
+}
+
+// bumpUntil bumps tokens up to the first one matching stop outside
+// of brackets, trailing trivia is left for the enclosing node
+func (p *parser) bumpUntil(stop func(Kind) bool) {
+	end, depth := p.pos, 0
+	for ; end < len(p.tokens); end++ {
+		kind := p.tokens[end].kind
+		if depth == 0 && stop(kind) {
+			break
+		}
+		switch kind {
+		case ParenOpen, BracketOpen, BraceOpen:
+			depth++
+		case ParenClose, BracketClose, BraceClose:
+			depth = max(depth-1, 0)
+		}
+	}
+	for end > p.pos && p.tokens[end-1].kind.IsTrivia() {
+		end--
+	}
+	for p.pos < end {
+		p.bump()
+	}
+}
+
+// inlineTrivia bumps whitespace and comments up to the line end
+func (p *parser) inlineTrivia() {
+	for p.at(isKind(Whitespace, LineComment, BlockComment)) {
+		p.bump()
+	}
+}
+
+func (p *parser) trivia(kind Kind) bool {
+	return kind.IsTrivia()
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) at(match func(Kind) bool) bool {
+	return !p.eof() && match(p.tokens[p.pos].kind)
+}
+
+func (p *parser) atKeyword(keywords ...string) bool {
+	if !p.at(isKind(Ident)) {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(p.tokens[p.pos].text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextIs reports whether the next token after the current
+// one, skipping whitespace and comments, is one of kinds
+func (p *parser) nextIs(kinds ...Kind) bool {
+	for i := p.pos + 1; i < len(p.tokens); i++ {
+		kind := p.tokens[i].kind
+		if kind == Whitespace || kind == LineComment || kind == BlockComment {
+			continue
+		}
+		return isKind(kinds...)(kind)
+	}
+	return false
+}
+
+func (p *parser) bump() {
+	token := p.tokens[p.pos]
+	top := p.stack[len(p.stack)-1]
+	top.children = append(top.children, &green{kind: token.kind, text: token.text, width: len(token.text)})
+	p.pos++
+}
+
+func (p *parser) start(kind Kind) {
+	p.stack = append(p.stack, &frame{kind: kind})
+}
+
+// finish closes the current node and adds it to its parent
+func (p *parser) finish() *green {
+	top := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	node := newNode(top.kind, top.children)
+	if len(p.stack) > 0 {
+		parent := p.stack[len(p.stack)-1]
+		parent.children = append(parent.children, node)
+	}
+	return node
+}
+
+func isKind(kinds ...Kind) func(Kind) bool {
+	return func(kind Kind) bool {
+		for _, expected := range kinds {
+			if kind == expected {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func isLineEnd(kind Kind) bool {
+	return kind == Newline || kind == BraceClose
+}
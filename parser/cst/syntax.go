@@ -0,0 +1,169 @@
+package cst
+
+import "strings"
+
+// typed views on nodes, accessors return nil or
+// empty values for parts missing in the input
+
+type TableNode struct{ *Node }
+
+func (t TableNode) Name() *Node  { return t.Child(Name) }
+func (t TableNode) Body() *Node  { return t.Child(Body) }
+func (t TableNode) Alias() *Node { return childOf(t.Child(Alias), Name) }
+
+func (t TableNode) Settings() SettingsNode {
+	return SettingsNode{t.Child(Settings)}
+}
+
+func (t TableNode) Columns() []ColumnNode {
+	columns := make([]ColumnNode, 0)
+	for _, node := range childrenOf(t.Body(), Column) {
+		columns = append(columns, ColumnNode{node})
+	}
+	return columns
+}
+
+// Column returns the column declared as name
+func (t TableNode) Column(name string) (ColumnNode, bool) {
+	for _, column := range t.Columns() {
+		if column.Name().Value() == name {
+			return column, true
+		}
+	}
+	return ColumnNode{}, false
+}
+
+func (t TableNode) Note() *Node {
+	return childOf(t.Body(), Note)
+}
+
+type ColumnNode struct{ *Node }
+
+func (c ColumnNode) Name() *Node { return c.Child(Name) }
+func (c ColumnNode) Type() *Node { return c.Child(ColumnType) }
+
+func (c ColumnNode) Settings() SettingsNode {
+	return SettingsNode{c.Child(Settings)}
+}
+
+// SettingsNode is a bracketed settings list, the
+// embedded node is nil if the list is missing
+type SettingsNode struct{ *Node }
+
+func (s SettingsNode) Exists() bool {
+	return s.Node != nil
+}
+
+func (s SettingsNode) Items() []SettingNode {
+	items := make([]SettingNode, 0)
+	for _, node := range childrenOf(s.Node, Setting) {
+		items = append(items, SettingNode{node})
+	}
+	return items
+}
+
+// Item returns the setting with key, e.g. "ref" or "pk"
+func (s SettingsNode) Item(key string) (SettingNode, bool) {
+	for _, item := range s.Items() {
+		if strings.EqualFold(item.Key(), key) {
+			return item, true
+		}
+	}
+	return SettingNode{}, false
+}
+
+type SettingNode struct{ *Node }
+
+// Key returns the text before the colon or the whole
+// setting for flags like 'pk' and 'not null'
+func (s SettingNode) Key() string {
+	text := s.Text()
+	if key, _, found := strings.Cut(text, ":"); found {
+		return strings.TrimSpace(key)
+	}
+	return strings.TrimSpace(text)
+}
+
+// Value returns the text after the colon without quotes
+func (s SettingNode) Value() string {
+	_, value, found := strings.Cut(s.Text(), ":")
+	if !found {
+		return ""
+	}
+	return unquote(strings.TrimSpace(value))
+}
+
+type RefNode struct{ *Node }
+
+func (r RefNode) Name() *Node { return r.Child(Name) }
+
+// relation returns the node holding the endpoints,
+// the body for long declarations
+func (r RefNode) relation() *Node {
+	if body := r.Child(Body); body != nil {
+		return body
+	}
+	return r.Node
+}
+
+func (r RefNode) Endpoints() []*Node { return r.relation().ChildrenOf(Endpoint) }
+func (r RefNode) Operator() *Node    { return r.relation().Child(Operator) }
+func (r RefNode) Settings() SettingsNode {
+	return SettingsNode{r.relation().Child(Settings)}
+}
+
+type EnumNode struct{ *Node }
+
+func (e EnumNode) Name() *Node { return e.Child(Name) }
+
+func (e EnumNode) Values() []*Node {
+	return childrenOf(e.Child(Body), EnumValue)
+}
+
+type ProjectNode struct{ *Node }
+
+func (p ProjectNode) Name() *Node { return p.Child(Name) }
+
+func (p ProjectNode) Options() []*Node {
+	return childrenOf(p.Child(Body), Option)
+}
+
+func (p ProjectNode) Note() *Node {
+	return childOf(p.Child(Body), Note)
+}
+
+type TableGroupNode struct{ *Node }
+
+func (t TableGroupNode) Name() *Node { return t.Child(Name) }
+
+func (t TableGroupNode) Members() []*Node {
+	return childrenOf(t.Child(Body), Name)
+}
+
+type NoteNode struct{ *Node }
+
+func (n NoteNode) Name() *Node { return n.Child(Name) }
+
+// Content returns the note text without quotes
+func (n NoteNode) Content() string {
+	for _, token := range n.Tokens(false) {
+		if token.Kind() == String {
+			return unquote(token.Text())
+		}
+	}
+	return ""
+}
+
+func childOf(node *Node, kind Kind) *Node {
+	if node == nil {
+		return nil
+	}
+	return node.Child(kind)
+}
+
+func childrenOf(node *Node, kind Kind) []*Node {
+	if node == nil {
+		return []*Node{}
+	}
+	return node.ChildrenOf(kind)
+}
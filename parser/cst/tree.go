@@ -0,0 +1,166 @@
+package cst
+
+import "strings"
+
+// green is an immutable tree element without position, tokens
+// carry their text, nodes the concatenated width of their children
+type green struct {
+	kind     Kind
+	text     string
+	width    int
+	children []*green
+}
+
+func newNode(kind Kind, children []*green) *green {
+	width := 0
+	for _, child := range children {
+		width += child.width
+	}
+	return &green{kind: kind, width: width, children: children}
+}
+
+func (g *green) write(builder *strings.Builder) {
+	if g.kind.IsToken() {
+		builder.WriteString(g.text)
+		return
+	}
+	for _, child := range g.children {
+		child.write(builder)
+	}
+}
+
+// Node is a token or node of the syntax tree
+// together with its parent and byte offset
+type Node struct {
+	green  *green
+	parent *Node
+	offset int
+}
+
+func (n *Node) Kind() Kind {
+	return n.green.kind
+}
+
+func (n *Node) IsToken() bool {
+	return n.green.kind.IsToken()
+}
+
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Offset is the byte offset of the node in the input
+func (n *Node) Offset() int {
+	return n.offset
+}
+
+// End is the byte offset after the node
+func (n *Node) End() int {
+	return n.offset + n.green.width
+}
+
+// Text returns the input covered by the node including trivia
+func (n *Node) Text() string {
+	if n.IsToken() {
+		return n.green.text
+	}
+	var builder strings.Builder
+	n.green.write(&builder)
+	return builder.String()
+}
+
+// Value returns the text of the node without leading and trailing
+// trivia, quotes of a single string literal are removed
+func (n *Node) Value() string {
+	significant := n.Tokens(false)
+	if len(significant) == 1 && significant[0].Kind() == String {
+		return unquote(significant[0].Text())
+	}
+	start, end := n.Significant()
+	return n.Text()[start-n.offset : end-n.offset]
+}
+
+// Children returns the direct children, tokens included
+func (n *Node) Children() []*Node {
+	children := make([]*Node, 0, len(n.green.children))
+	offset := n.offset
+	for _, child := range n.green.children {
+		children = append(children, &Node{green: child, parent: n, offset: offset})
+		offset += child.width
+	}
+	return children
+}
+
+// Child returns the first direct child of kind
+func (n *Node) Child(kind Kind) *Node {
+	for _, child := range n.Children() {
+		if child.Kind() == kind {
+			return child
+		}
+	}
+	return nil
+}
+
+// ChildrenOf returns the direct children of kind
+func (n *Node) ChildrenOf(kind Kind) []*Node {
+	children := make([]*Node, 0)
+	for _, child := range n.Children() {
+		if child.Kind() == kind {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Walk visits the node and its descendants in order,
+// children are skipped if visit returns false
+func (n *Node) Walk(visit func(*Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for _, child := range n.Children() {
+		child.Walk(visit)
+	}
+}
+
+// Tokens returns all tokens below the node in order,
+// trivia tokens only if trivia is set
+func (n *Node) Tokens(trivia bool) []*Node {
+	tokens := make([]*Node, 0)
+	n.Walk(func(node *Node) bool {
+		if node.IsToken() && (trivia || !node.Kind().IsTrivia()) {
+			tokens = append(tokens, node)
+		}
+		return true
+	})
+	return tokens
+}
+
+// Significant returns the range of the node without
+// leading and trailing trivia as byte offsets
+func (n *Node) Significant() (start int, end int) {
+	tokens := n.Tokens(false)
+	if len(tokens) == 0 {
+		return n.offset, n.offset
+	}
+	return tokens[0].Offset(), tokens[len(tokens)-1].End()
+}
+
+// Ancestor returns the closest ancestor of kind
+func (n *Node) Ancestor(kind Kind) *Node {
+	for parent := n.parent; parent != nil; parent = parent.parent {
+		if parent.Kind() == kind {
+			return parent
+		}
+	}
+	return nil
+}
+
+func unquote(text string) string {
+	for _, quote := range []string{"'''", "'", "\"", "`"} {
+		if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			return text[len(quote) : len(text)-len(quote)]
+		}
+	}
+	return text
+}
@@ -2,8 +2,9 @@ package explicitparser
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
@@ -11,47 +12,90 @@ type ColumnParser struct {
 	*Parser
 }
 
-func (c *ColumnParser) Parse() (*symbols.Column, []*symbols.Relationship, error) {
-	statement := &symbols.Column{}
-	relations := make([]*symbols.Relationship, 0)
+func (c *ColumnParser) Parse() (*ast.Column, error) {
+	statement := &ast.Column{}
 
 	// colum name
-	nameItem, found := c.expect(tokens.IDENT)
+	nameItem, name, found := c.parseName()
 	if !found {
-		return nil, relations, fmt.Errorf("found %q, expected column name", nameItem.value)
+		return nil, fmt.Errorf("found %q, expected column name", nameItem.value)
 	}
-	statement.Name = nameItem.value
-	statement.Position = nameItem.position
+	statement.Name = name
 
 	// column type
-	typeItem, found := c.expect(tokens.IDENT)
+	typeItem, columnType, found := c.parseName()
 	if !found {
-		return nil, relations, fmt.Errorf("found %q, expected column type", typeItem.value)
+		return nil, fmt.Errorf("found %q, expected column type", typeItem.value)
 	}
-	statement.Type = typeItem.value
+	statement.Type = columnType
+	// quoted types like "int unsigned" take no scheme or arguments
+	if typeItem.IsToken(tokens.IDENT) {
+		if err := c.parseTypeSuffix(statement.Type); err != nil {
+			return nil, err
+		}
+	}
+	statement.Span = ast.Span{From: statement.Name.From, To: statement.Type.To}
 
 	// look for constraints
 	item, found := c.expect(tokens.SQUARE_OPEN)
 	if !found {
 		if item.token != tokens.LINEBR {
-			return nil, relations, fmt.Errorf("found %q, expected column definition stop", item.value)
+			return nil, fmt.Errorf("found %q, expected column definition stop", item.value)
 		}
 	} else {
 		// constraints definition found
-		constraints, rels, err := c.parseConstraints()
+		settings, err := c.parseConstraints()
+		if err != nil {
+			return nil, fmt.Errorf("incorrect constraint declaration: %s", err.Error())
+		}
+		statement.Settings = settings
+		// the closing bracket is the last item
+		statement.To = c.buffer.current.position
+	}
+	return statement, nil
+}
+
+// parseTypeSuffix parses the scheme of enum types
+// like core.status and type arguments like decimal(10,2)
+func (c *ColumnParser) parseTypeSuffix(columnType *ast.Ident) error {
+	if item := c.scan(); item.IsToken(tokens.DOT) {
+		nameItem, found := c.expect(tokens.IDENT)
+		if !found {
+			return fmt.Errorf("found %s, expected type name after '.'", nameItem.token)
+		}
+		columnType.Value += "." + nameItem.value
+		columnType.To = nameItem.position
+	} else {
+		c.unscan()
+	}
+	if item := c.scan(); item.IsToken(tokens.ROUND_OPEN) {
+		closeItem, args, err := c.parseTypeArgs()
 		if err != nil {
-			return nil, relations, fmt.Errorf("incorrect constraint declaration: %s", err.Error())
+			return err
 		}
-		statement.Constraints = constraints
-		table := c.GetTableCtx()
-		if table != nil {
-			for _, relation := range rels {
-				relation.SchemeA = table.Scheme
-				relation.TableA = table.Name
-				relation.ColumnA = nameItem.value
-			}
+		columnType.Value += "(" + args + ")"
+		columnType.To = closeItem.position
+	} else {
+		c.unscan()
+	}
+	return nil
+}
+
+// parseTypeArgs parses the arguments of a column type up to the
+// closing ')', returns the closing item and the arguments
+func (c *ColumnParser) parseTypeArgs() (LexItem, string, error) {
+	var args []string
+	for {
+		item := c.scanWithoutWhitespace()
+		switch item.token {
+		case tokens.ROUND_CLOSE:
+			return item, strings.Join(args, ","), nil
+		case tokens.COMMA:
+			continue
+		case tokens.NUMBER, tokens.IDENT:
+			args = append(args, item.value)
+		default:
+			return item, "", fmt.Errorf("found %s, expected type argument", item.token)
 		}
-		relations = rels
 	}
-	return statement, relations, nil
 }
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
@@ -12,72 +12,117 @@ type ConstraintParser struct {
 	*Parser
 }
 
-func (c *ConstraintParser) Parse() ([]*symbols.Constraint, []*symbols.Relationship, error) {
-	var constraints []*symbols.Constraint
-	var relations []*symbols.Relationship
-	var lastToken int
+func (c *ConstraintParser) Parse() ([]*ast.Setting, error) {
+	var settings []*ast.Setting
+	lastToken := tokens.SQUARE_OPEN
 	for {
-		constraintItem := c.scanWithoutWhitespace()
+		constraintItem := c.scanWithoutWhitespace().asKeyword(tokens.CONS_DEFAULT)
 		switch constraintItem.token {
 		case tokens.SQUARE_CLOSE:
-			if len(constraints) == 0 && len(relations) == 0 {
-				return nil, nil, errors.New("empty constraints declaration")
+			if len(settings) == 0 {
+				return nil, errors.New("empty constraints declaration")
 			}
-			return constraints, relations, nil
+			return settings, nil
 		case tokens.COMMA:
 			// TODO: handle first token: ';'
 			if lastToken == tokens.COMMA {
-				return nil, nil, fmt.Errorf("found %q, expected constraint delimiter", constraintItem.value)
+				return nil, fmt.Errorf("found %s, expected constraint after %s", constraintItem.token, lastToken)
 			}
 		case tokens.CONS_PK:
-			constraints = append(constraints, &symbols.Constraint{Key: "", Value: constraintItem.value})
+			settings = append(settings, flag(constraintItem, constraintItem, constraintItem.value))
 		case tokens.CONS_PRIMARY:
 			item, found := c.expect(tokens.CONS_KEY)
 			if !found {
-				return nil, nil, fmt.Errorf("found %q, expected 'key' after 'primary'", item.value)
+				return nil, fmt.Errorf("found %q, expected 'key' after 'primary'", item.value)
 			}
-			constraints = append(constraints, &symbols.Constraint{Key: "", Value: "primary key"})
+			settings = append(settings, flag(constraintItem, item, "primary key"))
 		case tokens.CONS_INCREMENT:
 			fallthrough
 		case tokens.CONS_UNIQUE:
-			constraints = append(constraints, &symbols.Constraint{Key: "", Value: constraintItem.value})
+			settings = append(settings, flag(constraintItem, constraintItem, constraintItem.value))
 		case tokens.NOTE:
-			keyConstraint, err := c.parseKeyConstraint(tokens.NOTE)
+			keySetting, err := c.parseKeyConstraint(constraintItem)
 			if err != nil {
-				fmt.Println(err)
+				return nil, err
 			}
-			constraints = append(constraints, keyConstraint)
+			settings = append(settings, keySetting)
+		case tokens.CONS_DEFAULT:
+			item, found := c.expect(tokens.COLON)
+			if !found {
+				return nil, fmt.Errorf("found %q, expected ':' after 'default'", item.value)
+			}
+			literal, err := c.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			settings = append(settings, &ast.Setting{
+				Span:    ast.Span{From: constraintItem.position, To: literal.To},
+				Key:     "default",
+				Value:   literal.Source(),
+				Literal: literal,
+			})
 		case tokens.CONS_NOT:
 			item, found := c.expect(tokens.CONS_NULL)
 			if !found {
-				return nil, nil, fmt.Errorf("found %q, expected 'null' (not null)", item.value)
+				return nil, fmt.Errorf("found %q, expected 'null' (not null)", item.value)
 			}
-			constraints = append(constraints, &symbols.Constraint{Key: "", Value: item.value})
+			settings = append(settings, flag(constraintItem, item, "not null"))
 
 		case tokens.REF_LOW:
-			rel, err := c.parseRelationship(true)
+			ref, err := c.parseInlineRelationship()
 			if err != nil {
-				return nil, nil, err
+				return nil, err
+			}
+			ref.From = constraintItem.position
+			settings = append(settings, &ast.Setting{
+				Span:  ast.Span{From: constraintItem.position, To: ref.To},
+				Key:   "ref",
+				Value: ref.Type + " " + ref.Target.String(),
+				Ref:   ref,
+			})
+		case tokens.IDENT:
+			if !colorSettings[constraintItem.value] {
+				return nil, fmt.Errorf("found %q, expected contraint", constraintItem.value)
 			}
-			relations = append(relations, rel)
+			item, found := c.expect(tokens.COLON)
+			if !found {
+				return nil, fmt.Errorf("found %q, expected ':' after %q", item.value, constraintItem.value)
+			}
+			color, found := c.expect(tokens.COLOR)
+			if !found {
+				return nil, fmt.Errorf("found %q, expected color like #3498DB", color.value)
+			}
+			settings = append(settings, &ast.Setting{
+				Span:    span(constraintItem, color),
+				Key:     constraintItem.value,
+				Value:   color.value,
+				Literal: &ast.Literal{Span: span(color, color), Kind: tokens.COLOR, Value: color.value},
+			})
 		case tokens.UNKOWN:
-			return nil, nil, fmt.Errorf("parse_contraints: unkown token (unhandled): %q %s", constraintItem.token, constraintItem.value)
+			return nil, fmt.Errorf("unknown token %q in constraints", constraintItem.value)
 		default:
 			// error unkown token
-			if constraintItem.token == tokens.IDENT {
-				return nil, nil, fmt.Errorf("found %q, expected contraint", constraintItem.value)
-			}
-			return nil, nil, fmt.Errorf("unhandled non-ident item %q (%d), last: %d", constraintItem.value, constraintItem.token, lastToken)
+			return nil, fmt.Errorf("unexpected %s in constraints, after %s", constraintItem.token, lastToken)
 		}
+		lastToken = constraintItem.token
 	}
 }
 
-func (c *ConstraintParser) parseKeyConstraint(keyToken tokens.Token) (*symbols.Constraint, error) {
-	if keyToken != tokens.NOTE {
-		return nil, fmt.Errorf("unexpected keyed constraint: %q", keyToken)
+// colorSettings are the keys of color settings of tables and table groups
+var colorSettings = map[string]bool{"headercolor": true, "color": true}
+
+// flag returns a setting without key like 'pk' spanning first to last
+func flag(first LexItem, last LexItem, value string) *ast.Setting {
+	return &ast.Setting{Span: span(first, last), Value: value}
+}
+
+func (c *ConstraintParser) parseKeyConstraint(keyItem LexItem) (*ast.Setting, error) {
+	if keyItem.token != tokens.NOTE {
+		return nil, fmt.Errorf("unexpected keyed constraint: %s", keyItem.token)
 	}
-	constraint := &symbols.Constraint{
-		Key: "note",
+	constraint := &ast.Setting{
+		Span: span(keyItem, keyItem),
+		Key:  "note",
 	}
 
 	item, found := c.expect(tokens.COLON)
@@ -85,13 +130,37 @@ func (c *ConstraintParser) parseKeyConstraint(keyToken tokens.Token) (*symbols.C
 		return constraint, fmt.Errorf("found %q, expected ':' (key-value-delimiter missing)", item.value)
 	}
 
-	item, found = c.expect(tokens.QUOTATION)
-	if !found {
-		return constraint, fmt.Errorf("found %q, expected /\" (enclosing quotation start missing)", item.value)
+	value, valueSpan, err := c.parseString()
+	if err != nil {
+		return constraint, err
 	}
-
-	item = c.scanner.ScanComposite('"')
-	constraint.Value = item.value
+	constraint.Value = value
+	constraint.To = valueSpan.To
 
 	return constraint, nil
 }
+
+// parseLiteral parses a setting value like 0, -1.5, 'text',
+// #3498DB, true, null or `now()`
+func (c *ConstraintParser) parseLiteral() (*ast.Literal, error) {
+	item := c.scanWithoutWhitespace().asKeyword(tokens.BOOLEAN)
+	switch {
+	case item.In(tokens.G_LITERAL):
+		return &ast.Literal{Span: span(item, item), Kind: item.token, Value: item.value}, nil
+	case item.IsToken(tokens.REL_1T1):
+		// negative number
+		number := c.scan()
+		if !number.IsToken(tokens.NUMBER) {
+			return nil, fmt.Errorf("found %s, expected number after '-'", number.token)
+		}
+		return &ast.Literal{Span: span(item, number), Kind: tokens.NUMBER, Value: "-" + number.value}, nil
+	case item.IsToken(tokens.STRING, tokens.APOSTROPHE, tokens.QUOTATION):
+		c.unscan()
+		value, valueSpan, err := c.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Literal{Span: valueSpan, Kind: tokens.STRING, Value: value}, nil
+	}
+	return nil, fmt.Errorf("found %s, expected value", item.token)
+}
@@ -0,0 +1,60 @@
+package explicitparser
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+type EnumParser struct {
+	*Parser
+}
+
+// Parse parses an enum definition
+// e.g. Enum status { active [note: '...'] }
+func (e *EnumParser) Parse() (*ast.Enum, error) {
+	statement := &ast.Enum{}
+	keyword, name, _, _, err := e.ParseDefinitionHead(tokens.ENUM)
+	if err != nil {
+		return nil, err
+	}
+	statement.From = keyword.position
+	statement.Name = name
+
+	for {
+		item := e.scanWithoutWhitespace()
+		value := &ast.EnumValue{}
+		switch item.token {
+		case tokens.LINEBR:
+			continue
+		case tokens.BRACE_CLOSE:
+			statement.To = item.position
+			return statement, nil
+		case tokens.IDENT:
+			value.Name = ident(item)
+		case tokens.STRING, tokens.APOSTROPHE, tokens.QUOTATION:
+			e.unscan()
+			name, nameSpan, err := e.parseString()
+			if err != nil {
+				return nil, err
+			}
+			value.Name = &ast.Ident{Span: nameSpan, Value: name}
+		default:
+			return nil, fmt.Errorf("found %s, expected enum value", item.token)
+		}
+		value.Span = value.Name.Span
+
+		if item := e.scanWithoutWhitespace(); item.IsToken(tokens.SQUARE_OPEN) {
+			settings, err := e.parseConstraints()
+			if err != nil {
+				return nil, fmt.Errorf("incorrect enum value settings: %s", err.Error())
+			}
+			value.Settings = settings
+			value.To = e.buffer.current.position
+		} else {
+			e.unscan()
+		}
+		statement.Values = append(statement.Values, value)
+	}
+}
@@ -0,0 +1,110 @@
+package explicitparser
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+type ImportParser struct {
+	*Parser
+}
+
+// Parse parses an import statement, the introducing
+// 'use' or 'reuse' is expected to be already read.
+// use { table auth.users as u, enum status } from './auth.dbml'
+// use * from './auth.dbml'
+func (i *ImportParser) Parse(reuse bool) (*ast.Use, error) {
+	statement := &ast.Use{
+		Reuse: reuse,
+		Items: make([]*ast.UseItem, 0),
+	}
+
+	item := i.scanWithoutWhitespace()
+	switch item.token {
+	case tokens.ASTERISK:
+		statement.All = true
+	case tokens.BRACE_OPEN:
+		items, err := i.parseItems()
+		if err != nil {
+			return nil, err
+		}
+		statement.Items = items
+	default:
+		return nil, fmt.Errorf("found %q, expected '*' or '{' after import", item.value)
+	}
+
+	item = i.scanWithoutWhitespace().asKeyword(tokens.FROM)
+	if !item.IsToken(tokens.FROM) {
+		return nil, fmt.Errorf("found %q, expected 'from'", item.value)
+	}
+
+	quoteItem, found := i.expectAlternative(tokens.APOSTROPHE, tokens.QUOTATION)
+	if !found {
+		return nil, fmt.Errorf("found %q, expected quoted import path", quoteItem.value)
+	}
+	endChar := '"'
+	if quoteItem.IsToken(tokens.APOSTROPHE) {
+		endChar = '\''
+	}
+	pathItem := i.scanner.ScanComposite(endChar)
+	statement.Path = pathItem.value
+	statement.To = pathItem.position
+
+	return statement, nil
+}
+
+// parseItems parses the imported symbols up to the closing brace
+func (i *ImportParser) parseItems() ([]*ast.UseItem, error) {
+	items := make([]*ast.UseItem, 0)
+	for {
+		kindItem := i.scanWithoutWhitespace()
+		if kindItem.IsToken(tokens.LINEBR, tokens.COMMA) {
+			continue
+		}
+		if kindItem.IsToken(tokens.BRACE_CLOSE) {
+			return items, nil
+		}
+		if !kindItem.IsToken(tokens.IDENT, tokens.ENUM, tokens.TABLE) {
+			return nil, fmt.Errorf("found %q, expected import kind like 'table'", kindItem.value)
+		}
+
+		nameItem, found := i.expect(tokens.IDENT)
+		if !found {
+			return nil, fmt.Errorf("found %q, expected name of imported %s", nameItem.value, kindItem.value)
+		}
+		importItem := &ast.UseItem{
+			Span: span(kindItem, nameItem),
+			Kind: kindItem.value,
+			Name: &ast.Name{Span: span(nameItem, nameItem), Name: ident(nameItem)},
+		}
+
+		next := i.scanWithoutWhitespace().asKeyword(tokens.AS)
+		if next.IsToken(tokens.DOT) {
+			name2Item, found := i.expect(tokens.IDENT)
+			if !found {
+				return nil, fmt.Errorf("found %q, expected name after '.'", name2Item.value)
+			}
+			importItem.Name = &ast.Name{
+				Span:   span(nameItem, name2Item),
+				Scheme: ident(nameItem),
+				Name:   ident(name2Item),
+			}
+			importItem.To = name2Item.position
+			next = i.scanWithoutWhitespace().asKeyword(tokens.AS)
+		}
+		if next.IsToken(tokens.AS) {
+			aliasItem, found := i.expect(tokens.IDENT)
+			if !found {
+				return nil, fmt.Errorf("found %q, expected alias after 'as'", aliasItem.value)
+			}
+			importItem.Alias = ident(aliasItem)
+			importItem.To = aliasItem.position
+		} else {
+			i.unscan()
+		}
+
+		items = append(items, importItem)
+	}
+}
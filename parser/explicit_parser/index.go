@@ -0,0 +1,142 @@
+package explicitparser
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+type IndexParser struct {
+	*Parser
+}
+
+// Parse parses the indexes block of a table, the keyword is already consumed
+// e.g. indexes { (a, `lower(b)`) [unique, name: 'idx_ab'] }
+func (i *IndexParser) Parse() ([]*ast.Index, error) {
+	item, found := i.expect(tokens.BRACE_OPEN)
+	if !found {
+		return nil, fmt.Errorf("found %s, expected '{' after 'indexes'", item.token)
+	}
+
+	var indexes []*ast.Index
+	for {
+		item := i.scanWithoutWhitespace()
+		index := &ast.Index{}
+		switch item.token {
+		case tokens.LINEBR:
+			continue
+		case tokens.BRACE_CLOSE:
+			return indexes, nil
+		case tokens.IDENT, tokens.EXPRESSION, tokens.QUOTATION:
+			column := i.indexColumn(item)
+			index.Span = column.Span
+			index.Columns = []*ast.Ident{column}
+		case tokens.ROUND_OPEN:
+			columns, last, err := i.parseColumns()
+			if err != nil {
+				return nil, err
+			}
+			index.Span = span(item, last)
+			index.Columns = columns
+		default:
+			return nil, fmt.Errorf("found %s, expected index column", item.token)
+		}
+
+		if item := i.scanWithoutWhitespace(); item.IsToken(tokens.SQUARE_OPEN) {
+			settings, err := i.parseSettings()
+			if err != nil {
+				return nil, fmt.Errorf("incorrect index settings: %s", err.Error())
+			}
+			index.Settings = settings
+			index.To = i.buffer.current.position
+		} else {
+			i.unscan()
+		}
+		indexes = append(indexes, index)
+	}
+}
+
+// parseColumns parses composite index columns up to the closing ')'
+func (i *IndexParser) parseColumns() ([]*ast.Ident, LexItem, error) {
+	var columns []*ast.Ident
+	for {
+		item := i.scanWithoutWhitespace()
+		switch item.token {
+		case tokens.ROUND_CLOSE:
+			if len(columns) == 0 {
+				return nil, item, errors.New("empty composite index")
+			}
+			return columns, item, nil
+		case tokens.COMMA:
+			continue
+		case tokens.IDENT, tokens.EXPRESSION, tokens.QUOTATION:
+			columns = append(columns, i.indexColumn(item))
+		default:
+			return nil, item, fmt.Errorf("found %s, expected index column", item.token)
+		}
+	}
+}
+
+// parseSettings parses index settings up to the closing ']':
+// pk, unique, name: '...', type: hash and note: '...'
+func (i *IndexParser) parseSettings() ([]*ast.Setting, error) {
+	var settings []*ast.Setting
+	for {
+		item := i.scanWithoutWhitespace()
+		switch {
+		case item.IsToken(tokens.SQUARE_CLOSE):
+			return settings, nil
+		case item.IsToken(tokens.COMMA):
+			continue
+		case item.IsToken(tokens.CONS_PK, tokens.CONS_UNIQUE):
+			settings = append(settings, flag(item, item, item.value))
+		case item.IsToken(tokens.NOTE) || (item.IsToken(tokens.IDENT) && item.value == "name"):
+			colon, found := i.expect(tokens.COLON)
+			if !found {
+				return nil, fmt.Errorf("found %s, expected ':' after '%s'", colon.token, item.value)
+			}
+			value, valueSpan, err := i.parseString()
+			if err != nil {
+				return nil, err
+			}
+			settings = append(settings, &ast.Setting{
+				Span:  ast.Span{From: item.position, To: valueSpan.To},
+				Key:   item.value,
+				Value: value,
+			})
+		case item.IsToken(tokens.IDENT) && item.value == "type":
+			colon, found := i.expect(tokens.COLON)
+			if !found {
+				return nil, fmt.Errorf("found %s, expected ':' after 'type'", colon.token)
+			}
+			value, found := i.expect(tokens.IDENT)
+			if !found {
+				return nil, fmt.Errorf("found %s, expected index type like 'btree'", value.token)
+			}
+			settings = append(settings, &ast.Setting{
+				Span:  span(item, value),
+				Key:   "type",
+				Value: value.value,
+			})
+		default:
+			return nil, fmt.Errorf("found %s, expected index setting", item.token)
+		}
+	}
+}
+
+// indexColumn returns the column of an index,
+// expressions keep their backticks
+func (i *IndexParser) indexColumn(item LexItem) *ast.Ident {
+	column := ident(item)
+	switch {
+	case item.IsToken(tokens.EXPRESSION):
+		column.Value = "`" + item.value + "`"
+	case item.IsToken(tokens.QUOTATION):
+		// quoted column names like "key"
+		value := i.scanner.ScanComposite('"')
+		column = &ast.Ident{Span: span(item, value), Value: value.value}
+	}
+	return column
+}
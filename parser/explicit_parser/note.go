@@ -0,0 +1,80 @@
+package explicitparser
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+type NoteParser struct {
+	*Parser
+}
+
+// Parse parses a sticky note, the keyword is already consumed
+// e.g. Note name { "..." }
+func (n *NoteParser) Parse(keyword LexItem) (*ast.Note, error) {
+	nameItem, name, found := n.parseName()
+	if !found {
+		return nil, fmt.Errorf("found %s, expected note name", nameItem.token)
+	}
+	item, found := n.expect(tokens.BRACE_OPEN)
+	if !found {
+		return nil, fmt.Errorf("found %s, expected '{' after note name", item.token)
+	}
+	note, err := n.parseBlock(keyword)
+	if err != nil {
+		return nil, err
+	}
+	note.Name = name
+	return note, nil
+}
+
+// ParseValue parses the note of a table or project,
+// e.g. Note: 'text' or Note { 'text' }
+func (n *NoteParser) ParseValue(keyword LexItem) (*ast.Note, error) {
+	item := n.scanWithoutWhitespace()
+	switch item.token {
+	case tokens.COLON:
+		value, valueSpan, err := n.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Note{
+			Span:  ast.Span{From: keyword.position, To: valueSpan.To},
+			Value: value,
+		}, nil
+	case tokens.BRACE_OPEN:
+		return n.parseBlock(keyword)
+	}
+	return nil, fmt.Errorf("found %s, expected ':' or '{' after 'Note'", item.token)
+}
+
+// parseBlock parses the string and closing brace of a note body
+func (n *NoteParser) parseBlock(keyword LexItem) (*ast.Note, error) {
+	n.skipLineBreaks()
+	value, _, err := n.parseString()
+	if err != nil {
+		return nil, err
+	}
+	n.skipLineBreaks()
+	item, found := n.expect(tokens.BRACE_CLOSE)
+	if !found {
+		return nil, fmt.Errorf("found %s, expected '}' after note", item.token)
+	}
+	return &ast.Note{
+		Span:  span(keyword, item),
+		Value: value,
+	}, nil
+}
+
+// skipLineBreaks consumes whitespace and line breaks
+func (n *NoteParser) skipLineBreaks() {
+	for {
+		item := n.scanWithoutWhitespace()
+		if !item.IsToken(tokens.LINEBR) {
+			n.unscan()
+			return
+		}
+	}
+}
@@ -3,7 +3,7 @@ package explicitparser
 import (
 	"fmt"
 
-	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
@@ -11,28 +11,33 @@ type ProjectParser struct {
 	*Parser
 }
 
-func (p *ProjectParser) Parse() (*symbols.Project, error) {
-	project := &symbols.Project{
-		Options: make(map[string]string),
-	}
+func (p *ProjectParser) Parse() (*ast.Project, error) {
+	project := &ast.Project{}
 
-	position, _, name, err := p.ParseDefinitionHead(tokens.PROJECT)
+	keyword, name, _, _, err := p.ParseDefinitionHead(tokens.PROJECT)
 	if err != nil {
 		return nil, err
 	}
-	project.Position = position
-	project.Name = name
+	project.From = keyword.position
+	project.Name = name.Name
 
 	for {
 		keyItem := p.scanWithoutWhitespace()
 		switch keyItem.token {
 		case tokens.BRACE_CLOSE:
+			project.To = keyItem.position
 			return project, nil
 		case tokens.LINEBR:
 			continue
+		case tokens.NOTE_CAP:
+			note, err := p.parseNote(keyItem)
+			if err != nil {
+				return nil, err
+			}
+			project.Note = note
 		default:
-			if !keyItem.IsToken(tokens.G_PROJECT_OPTS) {
-				return nil, fmt.Errorf("found %q, expected project option key", keyItem.value)
+			if !keyItem.In(tokens.G_PROJECT_OPTS) {
+				return nil, fmt.Errorf("found %s, expected project setting", keyItem.token)
 			}
 
 			colonItem, found := p.expect(tokens.COLON)
@@ -40,13 +45,15 @@ func (p *ProjectParser) Parse() (*symbols.Project, error) {
 				return nil, fmt.Errorf("found %q, expected ':' (key-value-delimiter missing)", colonItem.value)
 			}
 
-			quoteStartItem, found := p.expect(tokens.QUOTATION)
-			if !found {
-				return nil, fmt.Errorf("found %q, expected /\" (enclosing quotation start missing)", quoteStartItem.value)
+			value, valueSpan, err := p.parseString()
+			if err != nil {
+				return nil, err
 			}
-
-			valueItem := p.scanner.ScanComposite('"')
-			project.Options[keyItem.value] = valueItem.value
+			project.Options = append(project.Options, &ast.Option{
+				Span:  ast.Span{From: keyItem.position, To: valueSpan.To},
+				Key:   ident(keyItem),
+				Value: value,
+			})
 
 		}
 	}
@@ -5,48 +5,90 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/strategy"
 	"github.com/h0rzn/dbml-lsp/parser/symbols"
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
+// Parser implements strategy.Strategy.
+// The state of a parse run lives in a fresh Parser per
+// call to Parse, so a Parser can be reused for every run.
 type Parser struct {
 	scanner  *Scanner
 	Symbols  *symbols.Storage
-	tableCtx *symbols.Table
+	blocks   []*strategy.Block
+	tableCtx *ast.Table
 	buffer   struct {
 		current LexItem
 		size    int
 	}
 }
 
-func NewParser(r io.Reader) *Parser {
-	return &Parser{
-		scanner: NewScanner(r),
-	}
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse parses all definitions read from reader.
+// The result is returned on error as well, holding the
+// symbols parsed so far and the error as diagnostic.
+func (p *Parser) Parse(reader io.Reader) (*strategy.Result, error) {
+	return p.ParseFragment(reader, 0, 0)
 }
 
-func (p *Parser) SetSymbols(storage *symbols.Storage) {
-	p.Symbols = storage
+// ParseFragment implements strategy.FragmentStrategy
+func (p *Parser) ParseFragment(reader io.Reader, line uint32, offset uint32) (*strategy.Result, error) {
+	run := &Parser{
+		scanner: NewScanner(reader),
+		Symbols: symbols.NewStorage(),
+		blocks:  make([]*strategy.Block, 0),
+	}
+	run.scanner.current = location{line: line, byte: offset}
+	err := run.parse()
+
+	result := &strategy.Result{
+		File:        strategy.File(run.blocks),
+		Symbols:     run.Symbols,
+		Diagnostics: make([]*analysis.Diagnostic, 0),
+		Tokens:      run.scanner.Items(),
+		Blocks:      run.blocks,
+	}
+	if err != nil {
+		result.Diagnostics = append(result.Diagnostics, &analysis.Diagnostic{
+			// the last scanned item is where parsing failed
+			Position: run.buffer.current.position,
+			Severity: analysis.SeverityError,
+			Code:     "parse-error",
+			Message:  err.Error(),
+		})
+	}
+	return result, err
 }
 
-func (p *Parser) SetTableCtx(table *symbols.Table) {
+func (p *Parser) SetTableCtx(table *ast.Table) {
 	p.tableCtx = table
 }
 
-func (p *Parser) GetTableCtx() *symbols.Table {
+func (p *Parser) GetTableCtx() *ast.Table {
 	return p.tableCtx
 }
 
-func (p *Parser) Parse() error {
+func (p *Parser) parse() error {
 	for {
 		item := p.scanWithoutWhitespace()
-		if item.IsToken(tokens.EOF | tokens.BRACE_CLOSE) {
+		if item.IsToken(tokens.EOF) {
 			break
 		}
 		if item.IsToken(tokens.LINEBR) {
 			continue
 		}
+		if item.IsToken(tokens.BRACE_CLOSE) {
+			return errors.New("found '}' without a matching '{'")
+		}
+		item = item.asKeyword(tokens.USE, tokens.REUSE)
 
+		var statement ast.Statement
 		switch item.token {
 		case tokens.PROJECT:
 			p.unscan()
@@ -54,7 +96,7 @@ func (p *Parser) Parse() error {
 			if err != nil {
 				return err
 			}
-			p.Symbols.SetProject(project)
+			statement = project
 
 		case tokens.TABLE:
 			p.unscan()
@@ -62,24 +104,48 @@ func (p *Parser) Parse() error {
 			if err != nil {
 				return err
 			}
-			p.Symbols.PutTable(table)
+			statement = table
+
+		case tokens.ENUM:
+			p.unscan()
+			enum, err := p.parseEnumDefinition()
+			if err != nil {
+				return err
+			}
+			statement = enum
+
+		case tokens.TABLEGROUP:
+			p.unscan()
+			group, err := p.parseTableGroupDefinition()
+			if err != nil {
+				return err
+			}
+			statement = group
+
+		case tokens.NOTE_CAP:
+			note, err := p.parseStickyNote(item)
+			if err != nil {
+				return err
+			}
+			statement = note
 
 		case tokens.REF_CAP:
 			// explicit pass of declaration type,
 			// introducing token is not expected
-			rel, err := p.parseRelationship(false)
+			rel, err := p.parseRelationship()
 			if err != nil {
 				return err
 			}
-			table, exists := p.Symbols.TableByName(rel.TableA)
-			if !exists {
-				return fmt.Errorf("reference: host table %q does not exist", rel.TableA)
-			}
-			table.References = append(table.References, rel)
-			err = p.Symbols.UpdateTable(table.Name, table)
+			rel.From = item.position
+			statement = rel
+
+		case tokens.USE, tokens.REUSE:
+			imp, err := p.parseImport(item.IsToken(tokens.REUSE))
 			if err != nil {
 				return err
 			}
+			imp.From = item.position
+			statement = imp
 
 		case tokens.SLASH:
 			item, exists := p.expect(tokens.SLASH)
@@ -89,50 +155,111 @@ func (p *Parser) Parse() error {
 
 			for {
 				item := p.scan()
-				if item.IsToken(tokens.LINEBR) {
+				if item.IsToken(tokens.LINEBR, tokens.EOF) {
 					break
 				}
 			}
+			continue
 
 		default:
 			return fmt.Errorf("unexpected: %q", item.value)
 		}
+
+		block := strategy.NewBlock(item.position.Line, p.lastLine(), statement)
+		if err := block.Declare(p.Symbols); err != nil {
+			return err
+		}
+		p.blocks = append(p.blocks, block)
 	}
 
 	return nil
 }
 
-func (p *Parser) ParseDefinitionHead(startToken tokens.Token) (position tokens.Position, scheme string, name string, err error) {
-	startItem, found := p.expect(startToken)
+// insignificant tokens do not end a block
+var insignificant = tokens.NewSet(tokens.WHITESPACE, tokens.LINEBR, tokens.EOF)
+
+// lastLine returns the line of the last item consumed by the
+// parser, skipping whitespace, line breaks and an unscanned item
+func (p *Parser) lastLine() uint32 {
+	items := p.scanner.Items()
+	last := len(items) - 1 - p.buffer.size
+	for ; last > 0; last-- {
+		if !insignificant.Has(items[last].Token) {
+			break
+		}
+	}
+	if last < 0 {
+		return 0
+	}
+	return items[last].Position.Line
+}
+
+// ParseDefinitionHead parses heads like 'Table scheme.name as alias [settings] {'
+// and returns the introducing keyword item, names can be quoted like "order items"
+func (p *Parser) ParseDefinitionHead(startToken tokens.Token) (keyword LexItem, name *ast.Name, alias *ast.Ident, settings []*ast.Setting, err error) {
+	keyword, found := p.expect(startToken)
 	if !found {
-		return position, scheme, name, fmt.Errorf("found %q, expected definition type", startItem.value)
+		return keyword, nil, nil, nil, fmt.Errorf("found %q, expected definition type", keyword.value)
 	}
 
-	nameItem, found := p.expect(tokens.IDENT)
+	nameItem, first, found := p.parseName()
 	if !found {
-		return position, scheme, name, fmt.Errorf("found %q, expected definition name declaration", nameItem.value)
+		return keyword, nil, nil, nil, fmt.Errorf("found %q, expected definition name declaration", nameItem.value)
 	}
 
 	nextItem := p.scan()
 	if nextItem.IsToken(tokens.DOT) {
-		name2Item, found := p.expect(tokens.IDENT)
+		name2Item, second, found := p.parseName()
 		if !found {
-			return position, scheme, name, fmt.Errorf("found %q, expected name after '.'", name2Item.value)
+			return keyword, nil, nil, nil, fmt.Errorf("found %q, expected name after '.'", name2Item.value)
+		}
+		name = &ast.Name{
+			Span:   ast.Span{From: first.From, To: second.To},
+			Scheme: first,
+			Name:   second,
 		}
-		name = name2Item.value
-		scheme = nameItem.value
 	} else if nextItem.IsToken(tokens.WHITESPACE) {
-		name = nameItem.value
+		name = &ast.Name{Span: first.Span, Name: first}
 	} else {
 		// unhandled token
-		return position, scheme, name, fmt.Errorf("unexpected %q", nextItem.value)
+		return keyword, nil, nil, nil, fmt.Errorf("unexpected %q", nextItem.value)
+	}
+
+	aliasItem := p.scanWithoutWhitespace().asKeyword(tokens.AS)
+	if aliasItem.IsToken(tokens.AS) {
+		aliasItem, found = p.expect(tokens.IDENT)
+		if !found {
+			return keyword, nil, nil, nil, fmt.Errorf("found %q, expected alias after 'as'", aliasItem.value)
+		}
+		alias = ident(aliasItem)
+	} else {
+		p.unscan()
+	}
+
+	if settingsItem := p.scanWithoutWhitespace(); settingsItem.IsToken(tokens.SQUARE_OPEN) {
+		settings, err = p.parseConstraints()
+		if err != nil {
+			return keyword, nil, nil, nil, fmt.Errorf("incorrect definition settings: %s", err.Error())
+		}
+	} else {
+		p.unscan()
 	}
 
 	_, found = p.expectSequence(tokens.BRACE_OPEN, tokens.LINEBR)
 	if !found {
-		return position, scheme, name, errors.New("found ?, expected delimiter '{' for definition head end")
+		return keyword, nil, nil, nil, errors.New("found ?, expected delimiter '{' for definition head end")
 	}
-	return startItem.position, scheme, name, nil
+	return keyword, name, alias, settings, nil
+}
+
+// ident returns the identifier node of item
+func ident(item LexItem) *ast.Ident {
+	return &ast.Ident{Span: span(item, item), Value: item.value}
+}
+
+// span returns the range from the first to the last item
+func span(first LexItem, last LexItem) ast.Span {
+	return ast.Span{From: first.position, To: last.position}
 }
 
 // scan returns next token from scanner.
@@ -159,6 +286,34 @@ func (p *Parser) unscan() {
 	p.buffer.size = 1
 }
 
+// parseString parses a quoted string 'text', "text" or a multi-line string,
+// returns the value and the span from the opening quote
+func (p *Parser) parseString() (string, ast.Span, error) {
+	item := p.scanWithoutWhitespace()
+	switch {
+	case item.IsToken(tokens.STRING):
+		return item.value, span(item, item), nil
+	case item.IsToken(tokens.APOSTROPHE, tokens.QUOTATION):
+		value := p.scanner.ScanComposite([]rune(item.value)[0])
+		return value.value, span(item, value), nil
+	}
+	return "", ast.Span{}, fmt.Errorf("found %s, expected string", item.token)
+}
+
+// parseName parses an identifier or a quoted name like "order items",
+// returns the first item, the name and whether a name was found
+func (p *Parser) parseName() (LexItem, *ast.Ident, bool) {
+	item := p.scanWithoutWhitespace()
+	switch {
+	case item.IsToken(tokens.IDENT):
+		return item, ident(item), true
+	case item.IsToken(tokens.QUOTATION):
+		value := p.scanner.ScanComposite('"')
+		return item, &ast.Ident{Span: span(item, value), Value: value.value}, true
+	}
+	return item, nil, false
+}
+
 // scanWithoutWhitespace scans next token ignoring whitespace
 func (p *Parser) scanWithoutWhitespace() LexItem {
 	item := p.scan()
@@ -168,25 +323,60 @@ func (p *Parser) scanWithoutWhitespace() LexItem {
 	return item
 }
 
-func (p *Parser) parseProjectDefinition() (*symbols.Project, error) {
+func (p *Parser) parseProjectDefinition() (*ast.Project, error) {
 	parser := &ProjectParser{p}
 	return parser.Parse()
 }
 
-func (p *Parser) parseTableDefinition() (*symbols.Table, error) {
+func (p *Parser) parseTableDefinition() (*ast.Table, error) {
 	parser := &TableParser{p}
 	return parser.Parse()
 }
 
-func (p *Parser) parseRelationship(inline bool) (*symbols.Relationship, error) {
+func (p *Parser) parseEnumDefinition() (*ast.Enum, error) {
+	parser := &EnumParser{p}
+	return parser.Parse()
+}
+
+func (p *Parser) parseTableGroupDefinition() (*ast.TableGroup, error) {
+	parser := &TableGroupParser{p}
+	return parser.Parse()
+}
+
+func (p *Parser) parseIndexes() ([]*ast.Index, error) {
+	parser := &IndexParser{p}
+	return parser.Parse()
+}
+
+func (p *Parser) parseImport(reuse bool) (*ast.Use, error) {
+	parser := &ImportParser{p}
+	return parser.Parse(reuse)
+}
+
+func (p *Parser) parseStickyNote(keyword LexItem) (*ast.Note, error) {
+	parser := &NoteParser{p}
+	return parser.Parse(keyword)
+}
+
+func (p *Parser) parseNote(keyword LexItem) (*ast.Note, error) {
+	parser := &NoteParser{p}
+	return parser.ParseValue(keyword)
+}
+
+func (p *Parser) parseRelationship() (*ast.Ref, error) {
 	parser := &RelationshipParser{p}
-	return parser.Parse(inline)
+	return parser.Parse()
+}
+
+func (p *Parser) parseInlineRelationship() (*ast.InlineRef, error) {
+	parser := &RelationshipParser{p}
+	return parser.ParseInline()
 }
 
 // parseColumnDefinition parses a column definition.
 // e.g. id integer [pk, unique]
 // returns a column statement and error
-func (p *Parser) parseColumnDefinition() (*symbols.Column, []*symbols.Relationship, error) {
+func (p *Parser) parseColumnDefinition() (*ast.Column, error) {
 	parser := &ColumnParser{p}
 	return parser.Parse()
 }
@@ -195,7 +385,7 @@ func (p *Parser) parseColumnDefinition() (*symbols.Column, []*symbols.Relationsh
 // this function expects the opening square bracket '[' to be already read
 // a, b, c]
 // ^ starting position
-func (p *Parser) parseConstraints() ([]*symbols.Constraint, []*symbols.Relationship, error) {
+func (p *Parser) parseConstraints() ([]*ast.Setting, error) {
 	parser := &ConstraintParser{p}
 	return parser.Parse()
 }
@@ -210,12 +400,7 @@ func (p *Parser) expect(expected tokens.Token) (item LexItem, found bool) {
 
 func (p *Parser) expectAlternative(expected ...tokens.Token) (item LexItem, found bool) {
 	item = p.scanWithoutWhitespace()
-	for _, foundToken := range expected {
-		if item.IsToken(foundToken) {
-			return item, true
-		}
-	}
-	return item, false
+	return item, item.IsToken(expected...)
 }
 
 func (p *Parser) expectSequence(expected ...tokens.Token) (item []LexItem, found bool) {
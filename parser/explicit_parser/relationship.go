@@ -3,7 +3,7 @@ package explicitparser
 import (
 	"fmt"
 
-	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
@@ -11,30 +11,57 @@ type RelationshipParser struct {
 	*Parser
 }
 
-func (r *RelationshipParser) Parse(inline bool) (*symbols.Relationship, error) {
-	relationship := &symbols.Relationship{}
-	var item LexItem
+// Parse parses a Ref statement, the introducing 'Ref' is expected to be
+// already read. Ref name: a.id > b.id and Ref name { a.id > b.id }
+func (r *RelationshipParser) Parse() (*ast.Ref, error) {
+	var name *ast.Ident
+	item := r.scanWithoutWhitespace()
+	// catch optional name
+	if item.IsToken(tokens.IDENT, tokens.QUOTATION) {
+		r.unscan()
+		_, name, _ = r.parseName()
+		item = r.scanWithoutWhitespace()
+	}
 
-	if !inline {
+	long := item.IsToken(tokens.BRACE_OPEN)
+	if long {
 		item = r.scanWithoutWhitespace()
-		// catch optional name
-		if item.IsToken(tokens.IDENT) {
-			relationship.Name = item.value
-			item = r.scanWithoutWhitespace()
+		if !item.IsToken(tokens.LINEBR) {
+			return nil, fmt.Errorf("found %q, expected linebr after '{' for long relationsip declaration %d", item.value, item.position.Line)
 		}
+		r.skipLineBreaks()
+	} else if !item.IsToken(tokens.COLON) {
+		return nil, fmt.Errorf("found %q, expeceted ':' after 'Ref' with name", item.value)
+	}
+
+	relationship, err := r.parseLong()
+	if err != nil {
+		return nil, err
+	}
+	relationship.Name = name
 
-		if item.IsToken(tokens.BRACE_OPEN) {
-			item = r.scanWithoutWhitespace()
-			if (item.token & tokens.LINEBR) == 0 {
-				return nil, fmt.Errorf("found %q, expected linebr after '{' for long relationsip declaration %d", item.value, item.position.Line)
-			}
-		} else if !item.IsToken(tokens.COLON) {
-			return relationship, fmt.Errorf("found %q, expeceted ':' after 'Ref' with name", item.value)
+	if long {
+		r.skipLineBreaks()
+		item, found := r.expect(tokens.BRACE_CLOSE)
+		if !found {
+			return nil, fmt.Errorf("found %q, expected '}' after long relationship declaration", item.value)
 		}
+		relationship.To = item.position
+	}
+	return relationship, nil
+}
 
-		relationship, err := r.parseLong()
-		return relationship, err
+// skipLineBreaks consumes the line breaks and whitespace up to the next item
+func (r *RelationshipParser) skipLineBreaks() {
+	for item := r.scanWithoutWhitespace(); item.IsToken(tokens.LINEBR); item = r.scanWithoutWhitespace() {
 	}
+	r.unscan()
+}
+
+// ParseInline parses the setting ': > table.column',
+// the introducing 'ref' is expected to be already read.
+func (r *RelationshipParser) ParseInline() (*ast.InlineRef, error) {
+	relationship := &ast.InlineRef{}
 
 	item, exists := r.expect(tokens.COLON)
 	if !exists {
@@ -42,85 +69,83 @@ func (r *RelationshipParser) Parse(inline bool) (*symbols.Relationship, error) {
 	}
 
 	item = r.scanWithoutWhitespace()
-	if !item.IsToken(tokens.G_RELATION_TYPE) {
+	if !item.In(tokens.G_RELATION_TYPE) {
 		return nil, fmt.Errorf("found %q, expected relationship declaration", item.value)
 	}
+	relationship.Type = item.value
 
-	sideRight, err := r.parseSide()
+	target, err := r.parseSide()
 	if err != nil {
 		return nil, err
 	}
-	if len(sideRight) > 2 {
-		relationship.SchemeB = sideRight[0].value
-		relationship.TableB = sideRight[1].value
-		relationship.ColumnB = sideRight[2].value
-	} else {
-		relationship.TableB = sideRight[0].value
-		relationship.ColumnB = sideRight[1].value
-	}
+	relationship.Target = target
+	relationship.To = target.To
 
 	return relationship, nil
 }
 
-func (r *RelationshipParser) parseLong() (*symbols.Relationship, error) {
-	relationship := &symbols.Relationship{}
-	sideLeft, err := r.parseSide()
+func (r *RelationshipParser) parseLong() (*ast.Ref, error) {
+	relationship := &ast.Ref{}
+	left, err := r.parseSide()
 	if err != nil {
 		return nil, err
 	}
-	if len(sideLeft) > 2 {
-		relationship.SchemeA = sideLeft[0].value
-		relationship.TableA = sideLeft[1].value
-		relationship.ColumnA = sideLeft[2].value
-	} else {
-		relationship.TableA = sideLeft[0].value
-		relationship.ColumnA = sideLeft[1].value
-	}
+	relationship.Left = left
 
 	item := r.scanWithoutWhitespace()
-	if !item.IsToken(tokens.G_RELATION_TYPE) {
+	if !item.In(tokens.G_RELATION_TYPE) {
 		return nil, fmt.Errorf("found %q, expected relationship declaration", item.value)
 	}
 	relationship.Type = item.value
 
-	sideRight, err := r.parseSide()
+	right, err := r.parseSide()
 	if err != nil {
 		return nil, err
 	}
-
-	if len(sideRight) > 2 {
-		relationship.SchemeB = sideRight[0].value
-		relationship.TableB = sideRight[1].value
-		relationship.ColumnB = sideRight[2].value
-	} else {
-		relationship.TableB = sideRight[0].value
-		relationship.ColumnB = sideRight[1].value
-	}
+	relationship.Right = right
+	relationship.To = right.To
 
 	return relationship, nil
 }
 
-func (r *RelationshipParser) parseSide() ([]LexItem, error) {
-	var relationSide []LexItem
-
-	// minimum requirement is: tableA.columnA
-	items, exists := r.expectSequence(tokens.IDENT, tokens.DOT, tokens.IDENT)
-	if !exists {
-		return relationSide, fmt.Errorf("found %v, expected table.column or scheme.table.column for relationship declaration", items)
+// parseSide parses an endpoint table.column or scheme.table.column,
+// the names can be quoted like "order items"."order id"
+func (r *RelationshipParser) parseSide() (*ast.Endpoint, error) {
+	names := make([]*ast.Ident, 0, 3)
+	for {
+		item, name, found := r.parseName()
+		if !found {
+			return nil, fmt.Errorf("found %q, expected table.column or scheme.table.column for relationship declaration", item.value)
+		}
+		names = append(names, name)
+		if len(names) == 3 {
+			break
+		}
+		if dot := r.scan(); !dot.IsToken(tokens.DOT) {
+			r.unscan()
+			break
+		}
+		if next := r.scan(); next.IsToken(tokens.ROUND_OPEN) {
+			return nil, fmt.Errorf("found %q, composite relationships like table.(a, b) are not supported", next.value)
+		}
+		r.unscan()
+	}
+	if len(names) == 1 {
+		return nil, fmt.Errorf("found %q, expected '.' and column name after table for relationship declaration", names[0].Value)
 	}
-	relationSide = append(relationSide, items[0], items[2])
 
-	// if one more ident attached,
-	// then the first ident is scheme not table
-	// -> schemeA.tableA.columnA
-	item := r.scanWithoutWhitespace()
-	if item.IsToken(tokens.DOT) {
-		item = r.scanWithoutWhitespace()
-		if item.IsToken(tokens.IDENT) {
-			relationSide = append(relationSide, item)
+	column := names[len(names)-1]
+	endpoint := &ast.Endpoint{
+		Span:    ast.Span{From: names[0].From, To: column.To},
+		Table:   &ast.Name{Span: names[len(names)-2].Span, Name: names[len(names)-2]},
+		Columns: []*ast.Ident{column},
+	}
+	if len(names) == 3 {
+		endpoint.Table = &ast.Name{
+			Span:   ast.Span{From: names[0].From, To: names[1].To},
+			Scheme: names[0],
+			Name:   names[1],
 		}
-	} else {
-		r.unscan()
 	}
-	return relationSide, nil
+	return endpoint, nil
 }
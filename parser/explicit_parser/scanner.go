@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"strings"
 	"unicode"
 
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
@@ -15,122 +16,207 @@ type LexItem struct {
 	position tokens.Position
 }
 
-func (l *LexItem) IsToken(expected tokens.Token) bool {
-	return (l.token & expected) != 0
+// IsToken reports whether the item is one of the expected tokens
+func (l *LexItem) IsToken(expected ...tokens.Token) bool {
+	for _, token := range expected {
+		if l.token == token {
+			return true
+		}
+	}
+	return false
+}
+
+// asKeyword returns the item as the contextual keyword it spells
+// if that is one of allowed, otherwise the item is unchanged
+func (l LexItem) asKeyword(allowed ...tokens.Token) LexItem {
+	if l.token != tokens.IDENT {
+		return l
+	}
+	keyword := tokens.MapKeyword(l.value)
+	for _, token := range allowed {
+		if keyword == token {
+			l.token = keyword
+			return l
+		}
+	}
+	return l
+}
+
+// In reports whether the item is a member of set
+func (l *LexItem) In(set tokens.Set) bool {
+	return set.Has(l.token)
 }
 
 type Scanner struct {
 	reader *bufio.Reader
-	// current focused line
+	// current is the location of the next rune,
+	// previous the one before the last read
+	current  location
+	previous location
+	// items holds every scanned item
+	items []tokens.Item
+}
+
+// location is a point in the document
+type location struct {
 	line uint32
-	// current offset in focused line
+	// column in UTF-16 code units
 	offset uint32
+	// byte offset in the document
+	byte uint32
 }
 
 func NewScanner(reader io.Reader) *Scanner {
 	return &Scanner{
 		reader: bufio.NewReader(reader),
-		line:   0,
-		offset: 0,
 	}
 }
 
-func (s *Scanner) Reset() error {
-	s.reader.Reset(s.reader)
-	return nil
+// Items returns the items scanned so far
+func (s *Scanner) Items() []tokens.Item {
+	return s.items
+}
+
+func (s *Scanner) record(item LexItem) LexItem {
+	s.items = append(s.items, tokens.Item{
+		Token:    item.token,
+		Value:    item.value,
+		Position: item.position,
+	})
+	return item
 }
 
 // Scan fetches next token and literal value
 func (s *Scanner) Scan() LexItem {
+	return s.record(s.scan())
+}
+
+func (s *Scanner) scan() LexItem {
+	start := s.current
 	char := s.read()
 	if isWhitespace(char) {
 		s.unread()
 		return s.scanWhitespace()
 	}
-	if isLetter(char) {
+	if isLetter(char) || char == '_' {
 		s.unread()
 		return s.scanIdent()
 	}
+	if isDigit(char) {
+		s.unread()
+		return s.scanNumber()
+	}
+	if char == '#' && isHexDigit(s.peek()) {
+		return s.scanColor(start)
+	}
+	if char == '`' {
+		return s.scanExpression(start)
+	}
+	if char == '\'' && s.peekTriple() {
+		return s.scanMultiline(start)
+	}
 
 	current := tokens.MapChar(char)
-	if current == tokens.LINEBR {
-		s.line += 1
-		s.offset = 0
-	} else if (current & tokens.REL_1TM) != 0 {
+	if current == tokens.REL_1TM {
 		// could potentially be <> and not just <
 		if s.read() != '>' {
 			s.unread()
 		} else {
-			s.offset += 1
 			return LexItem{
-				value: "<>",
-				token: tokens.REL_MTN,
-				position: tokens.Position{
-					Line:   s.line,
-					Offset: s.offset,
-					Len:    2,
-				},
+				value:    "<>",
+				token:    tokens.REL_MTN,
+				position: s.position(start, s.current),
 			}
 		}
 	}
 
-	item := LexItem{
-		value: string(char),
-		token: current,
-		position: tokens.Position{
-			Line:   s.line,
-			Offset: s.offset,
-			Len:    1,
-		},
+	return LexItem{
+		value:    string(char),
+		token:    current,
+		position: s.position(start, s.current),
 	}
-
-	return item
-
 }
 
+// ScanComposite reads everything up to endChar as one item,
+// endChar is consumed but not part of the item, unless escaped
 func (s *Scanner) ScanComposite(endChar rune) LexItem {
-	var buf bytes.Buffer
-	buf.WriteRune(s.read())
+	return s.record(s.scanComposite(endChar))
+}
 
-	var length uint32 = 1
+func (s *Scanner) scanComposite(endChar rune) LexItem {
+	var buf bytes.Buffer
+	start, end := s.current, s.current
 	for {
+		end = s.current
 		char := s.read()
 		if char == tokens.EOFChar || char == endChar {
 			break
-		} else {
-			length += 1
-			buf.WriteRune(char)
 		}
+		if char == '\\' && s.peek() == endChar {
+			// escaped end char like \'
+			char = s.read()
+		}
+		buf.WriteRune(char)
 	}
 	literal := buf.String()
-	item := LexItem{
-		value: literal,
-		token: tokens.MapLiteral(literal),
-		position: tokens.Position{
-			Line:   s.line,
-			Offset: s.offset - length,
-			Len:    length,
-		},
+	return LexItem{
+		value:    literal,
+		token:    tokens.MapLiteral(literal),
+		position: s.position(start, end),
 	}
-	return item
+}
 
+// position returns the position of an item from start to end
+func (s *Scanner) position(start location, end location) tokens.Position {
+	position := tokens.Position{
+		Line:   start.line,
+		Offset: start.offset,
+		Byte:   start.byte,
+	}
+	if end.line == start.line {
+		position.Len = end.offset - start.offset
+	} else {
+		position.EndLine = end.line
+		position.EndOffset = end.offset
+	}
+	return position
 }
 
 // read reads the next rune (char) from the (buffered) reader.
 // Returns the rune(0) if an error occurs (or eofChar is returned).
 func (s *Scanner) read() rune {
-	char, _, err := s.reader.ReadRune()
+	char, size, err := s.reader.ReadRune()
 	if err != nil {
 		return tokens.EOFChar
 	}
-	s.offset += 1
+	s.previous = s.current
+	s.current.byte += uint32(size)
+	if char == '\n' {
+		s.current.line += 1
+		s.current.offset = 0
+	} else {
+		s.current.offset += tokens.UTF16RuneLen(char)
+	}
 	return char
 }
 
-// unread puts the last read rune back on the reader
+// peek returns the next rune without consuming it
+func (s *Scanner) peek() rune {
+	char := s.read()
+	if char != tokens.EOFChar {
+		s.unread()
+	}
+	return char
+}
+
+// unread puts the last read rune back on the reader,
+// only one rune can be unread at a time
 func (s *Scanner) unread() error {
-	s.offset -= 1
-	return s.reader.UnreadRune()
+	err := s.reader.UnreadRune()
+	if err == nil {
+		s.current = s.previous
+	}
+	return err
 }
 
 //
@@ -139,10 +225,10 @@ func (s *Scanner) unread() error {
 
 // scanWhitespace consumes current rune and contigous whitespace
 func (s *Scanner) scanWhitespace() LexItem {
+	start := s.current
 	var buf bytes.Buffer
 	buf.WriteRune(s.read())
 
-	var length uint32 = 1
 	for {
 		char := s.read()
 		if char == tokens.EOFChar {
@@ -151,53 +237,133 @@ func (s *Scanner) scanWhitespace() LexItem {
 			s.unread()
 			break
 		} else {
-			length += 1
 			buf.WriteRune(char)
 		}
 	}
-	item := LexItem{
-		value: "",
-		token: tokens.WHITESPACE,
-		position: tokens.Position{
-			Line:   s.line,
-			Offset: s.offset - length,
-			Len:    length,
-		},
+	return LexItem{
+		value:    "",
+		token:    tokens.WHITESPACE,
+		position: s.position(start, s.current),
 	}
-	return item
-	// return WHITESPACE, buf.String()
 }
 
-// scanWhitespace consumes current rune and contigous whitespace
+// scanIdent consumes current rune and contigous letters, digits and underscores
 func (s *Scanner) scanIdent() LexItem {
+	start := s.current
 	var buf bytes.Buffer
 	buf.WriteRune(s.read())
+	s.readWhile(&buf, isIdentChar)
+
+	literal := buf.String()
+	return LexItem{
+		value:    literal,
+		token:    tokens.MapLiteral(literal),
+		position: s.position(start, s.current),
+	}
+}
+
+// scanNumber consumes an integer or decimal like 10 or 10.25,
+// digits followed by letters or underscores form an identifier
+func (s *Scanner) scanNumber() LexItem {
+	start := s.current
+	var buf bytes.Buffer
+	s.readWhile(&buf, isDigit)
 
-	var length uint32 = 1
+	token := tokens.NUMBER
+	if isIdentChar(s.peek()) {
+		s.readWhile(&buf, isIdentChar)
+		token = tokens.IDENT
+	} else if s.peekDecimal() {
+		buf.WriteRune(s.read())
+		s.readWhile(&buf, isDigit)
+	}
+	return LexItem{
+		value:    buf.String(),
+		token:    token,
+		position: s.position(start, s.current),
+	}
+}
+
+// peekDecimal reports whether the next runes are a '.' followed by a digit
+func (s *Scanner) peekDecimal() bool {
+	next, err := s.reader.Peek(2)
+	return err == nil && next[0] == '.' && isDigit(rune(next[1]))
+}
+
+// scanColor consumes the hex digits of a color like #3498DB,
+// the leading '#' is already read
+func (s *Scanner) scanColor(start location) LexItem {
+	var buf bytes.Buffer
+	buf.WriteRune('#')
+	s.readWhile(&buf, isHexDigit)
+	return LexItem{
+		value:    buf.String(),
+		token:    tokens.COLOR,
+		position: s.position(start, s.current),
+	}
+}
+
+// scanExpression consumes an expression like `now()` up to the
+// closing backtick, the value excludes the backticks
+func (s *Scanner) scanExpression(start location) LexItem {
+	var buf bytes.Buffer
+	for {
+		char := s.read()
+		if char == tokens.EOFChar || char == '`' {
+			break
+		}
+		buf.WriteRune(char)
+	}
+	return LexItem{
+		value:    buf.String(),
+		token:    tokens.EXPRESSION,
+		position: s.position(start, s.current),
+	}
+}
+
+// peekTriple reports whether the next runes complete triple quotes
+func (s *Scanner) peekTriple() bool {
+	next, err := s.reader.Peek(2)
+	return err == nil && string(next) == "''"
+}
+
+// scanMultiline consumes a multi-line string in triple quotes
+// up to the closing quotes, the first quote is already read
+func (s *Scanner) scanMultiline(start location) LexItem {
+	s.read()
+	s.read()
+	var buf bytes.Buffer
 	for {
 		char := s.read()
 		if char == tokens.EOFChar {
 			break
-		} else if !isLetter(char) && !isDigit(char) {
-			s.unread()
+		}
+		if char == '\'' && s.peekTriple() {
+			s.read()
+			s.read()
 			break
-		} else {
-			length += 1
-			buf.WriteRune(char)
-
 		}
+		buf.WriteRune(char)
 	}
-	literal := buf.String()
-	item := LexItem{
-		value: literal,
-		token: tokens.MapLiteral(literal),
-		position: tokens.Position{
-			Line:   s.line,
-			Offset: s.offset - length,
-			Len:    length,
-		},
+	return LexItem{
+		value:    dedent(buf.String()),
+		token:    tokens.STRING,
+		position: s.position(start, s.current),
+	}
+}
+
+// readWhile consumes contigous runes matching class into buf
+func (s *Scanner) readWhile(buf *bytes.Buffer, class func(rune) bool) {
+	for {
+		char := s.read()
+		if char == tokens.EOFChar {
+			return
+		} else if !class(char) {
+			s.unread()
+			return
+		}
+		buf.WriteRune(char)
 	}
-	return item
 }
 
 //
@@ -216,3 +382,44 @@ func isLetter(ch rune) bool {
 func isDigit(ch rune) bool {
 	return unicode.IsDigit(ch)
 }
+
+func isIdentChar(ch rune) bool {
+	return isLetter(ch) || isDigit(ch) || ch == '_'
+}
+
+func isHexDigit(ch rune) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+// dedent removes the line breaks after the opening and before the
+// closing quotes of a multi-line string and the common indentation
+func dedent(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || width < indent {
+			indent = width
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		} else if indent > 0 {
+			// whitespace only line
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,7 +1,7 @@
 package explicitparser
 
 import (
-	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
@@ -9,33 +9,47 @@ type TableParser struct {
 	*Parser
 }
 
-func (t *TableParser) Parse() (*symbols.Table, error) {
-	statement := &symbols.Table{}
-	position, scheme, name, err := t.ParseDefinitionHead(tokens.TABLE)
+func (t *TableParser) Parse() (*ast.Table, error) {
+	statement := &ast.Table{}
+	keyword, name, alias, settings, err := t.ParseDefinitionHead(tokens.TABLE)
 	if err != nil {
 		return nil, err
 	}
-	statement.Position = position
-	statement.Scheme = scheme
+	statement.From = keyword.position
 	statement.Name = name
+	statement.Alias = alias
+	statement.Settings = settings
 	t.SetTableCtx(statement)
 
 	// column definitions
 	for {
 		columnItem := t.scanWithoutWhitespace()
-		switch columnItem.token {
+		switch columnItem.asKeyword(tokens.INDEXES).token {
 		case tokens.LINEBR:
 			continue
 		case tokens.BRACE_CLOSE:
+			statement.To = columnItem.position
 			return statement, nil
+		case tokens.NOTE_CAP:
+			// table note: Note: "..." or Note { "..." }
+			note, err := t.parseNote(columnItem)
+			if err != nil {
+				return nil, err
+			}
+			statement.Note = note
+		case tokens.INDEXES:
+			indexes, err := t.parseIndexes()
+			if err != nil {
+				return nil, err
+			}
+			statement.Indexes = append(statement.Indexes, indexes...)
 		default:
 			t.unscan()
-			column, relations, err := t.parseColumnDefinition()
+			column, err := t.parseColumnDefinition()
 			if err != nil {
 				return nil, err
 			}
 			statement.Columns = append(statement.Columns, column)
-			statement.References = relations
 		}
 	}
 }
@@ -0,0 +1,56 @@
+package explicitparser
+
+import (
+	"fmt"
+
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+type TableGroupParser struct {
+	*Parser
+}
+
+// Parse parses a table group definition
+// e.g. TableGroup billing [color: #3498DB] { invoices core.users }
+func (g *TableGroupParser) Parse() (*ast.TableGroup, error) {
+	statement := &ast.TableGroup{}
+	keyword, name, _, settings, err := g.ParseDefinitionHead(tokens.TABLEGROUP)
+	if err != nil {
+		return nil, err
+	}
+	if name.Scheme != nil {
+		return nil, fmt.Errorf("found %q, table groups have no scheme", name.Scheme.Value)
+	}
+	statement.From = keyword.position
+	statement.Name = name.Name
+	statement.Settings = settings
+
+	for {
+		item := g.scanWithoutWhitespace()
+		switch item.token {
+		case tokens.LINEBR:
+			continue
+		case tokens.BRACE_CLOSE:
+			statement.To = item.position
+			return statement, nil
+		case tokens.IDENT, tokens.QUOTATION:
+			g.unscan()
+		default:
+			return nil, fmt.Errorf("found %s, expected table name", item.token)
+		}
+
+		_, name, _ := g.parseName()
+		member := &ast.Name{Span: name.Span, Name: name}
+		if next := g.scan(); next.IsToken(tokens.DOT) {
+			nameItem, name2, found := g.parseName()
+			if !found {
+				return nil, fmt.Errorf("found %q, expected name after '.'", nameItem.value)
+			}
+			member = &ast.Name{Span: ast.Span{From: name.From, To: name2.To}, Scheme: name, Name: name2}
+		} else {
+			g.unscan()
+		}
+		statement.Members = append(statement.Members, member)
+	}
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/parser/strategy"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// Edit describes a change of the lines Start to End (inclusive)
+// of the previous text. Delta is the number of lines added
+// by the change, Bytes the number of bytes added, both
+// negative if text was removed.
+type Edit struct {
+	Start uint32
+	End   uint32
+	Delta int
+	Bytes int
+}
+
+// Reparse parses text, the result of applying edit to the text of previous.
+// Only the top-level blocks intersecting the edit are parsed again, the
+// other blocks are taken from previous: blocks before the edit are
+// declared with the symbols bound for previous, blocks after it are
+// moved by the edit and bound again.
+// Whenever that is not possible, e.g. previous failed or the changed
+// blocks do not parse on their own, the whole text is parsed instead.
+func (p *Parser) Reparse(previous *strategy.Result, text string, edit Edit) (*strategy.Result, error) {
+	fragments, supported := p.strategy.(strategy.FragmentStrategy)
+	if !supported || previous == nil || len(previous.Diagnostics) > 0 {
+		return p.ParseText(text)
+	}
+
+	// the region covers the edit and all blocks it touches,
+	// blocks sharing a line with the region are included as well
+	start, end := edit.Start, edit.End
+	first, last := len(previous.Blocks), -1
+	for changed := true; changed; {
+		changed = false
+		for i, block := range previous.Blocks {
+			if !block.Intersects(start, end) || (i >= first && i <= last) {
+				continue
+			}
+			start, end = min(start, block.Start), max(end, block.End)
+			first, last = min(first, i), max(last, i)
+			changed = true
+		}
+	}
+	if last < 0 {
+		// the edit is between blocks
+		first = len(previous.Blocks)
+		for i, block := range previous.Blocks {
+			if block.Start > end {
+				first = i
+				break
+			}
+		}
+		last = first - 1
+	}
+
+	newEnd := int(end) + edit.Delta
+	region, offset := lineRange(text, start, uint32(newEnd))
+	fragment, err := fragments.ParseFragment(strings.NewReader(region), start, uint32(offset))
+	if err != nil {
+		return p.ParseText(text)
+	}
+
+	blocks := make([]*strategy.Block, 0, len(previous.Blocks)-(last-first+1)+len(fragment.Blocks))
+	blocks = append(blocks, previous.Blocks[:first]...)
+	blocks = append(blocks, fragment.Blocks...)
+	for _, block := range previous.Blocks[last+1:] {
+		if edit.Delta != 0 || edit.Bytes != 0 {
+			block = block.Shifted(edit.Delta, edit.Bytes)
+		}
+		blocks = append(blocks, block)
+	}
+
+	storage := symbols.NewStorage()
+	for _, block := range blocks {
+		if err := block.Declare(storage); err != nil {
+			return p.ParseText(text)
+		}
+	}
+
+	result := &strategy.Result{
+		File:        strategy.File(blocks),
+		Symbols:     storage,
+		Diagnostics: fragment.Diagnostics,
+		Tokens:      spliceTokens(previous.Tokens, fragment.Tokens, start, end, edit),
+		Blocks:      blocks,
+	}
+	p.publish(result)
+	return result, nil
+}
+
+// lineRange returns the lines start to end (inclusive)
+// of text and the byte offset of line start
+func lineRange(text string, start uint32, end uint32) (string, int) {
+	from, line := 0, uint32(0)
+	for line < start {
+		next := strings.IndexByte(text[from:], '\n')
+		if next < 0 {
+			return "", len(text)
+		}
+		from += next + 1
+		line++
+	}
+
+	to := from
+	for line <= end {
+		next := strings.IndexByte(text[to:], '\n')
+		if next < 0 {
+			return text[from:], from
+		}
+		to += next + 1
+		line++
+	}
+	return text[from:to], from
+}
+
+// spliceTokens replaces the tokens in lines start to end of previous
+// by the fragment tokens and moves the following ones by the edit
+func spliceTokens(previous []tokens.Item, fragment []tokens.Item, start uint32, end uint32, edit Edit) []tokens.Item {
+	// tokens are in order of their position
+	before := sort.Search(len(previous), func(i int) bool { return previous[i].Position.Line >= start })
+	after := sort.Search(len(previous), func(i int) bool { return previous[i].Position.Line > end })
+
+	spliced := make([]tokens.Item, 0, before+len(fragment)+len(previous)-after)
+	spliced = append(spliced, previous[:before]...)
+	for _, item := range fragment {
+		if item.Token != tokens.EOF {
+			spliced = append(spliced, item)
+		}
+	}
+	if edit.Delta == 0 && edit.Bytes == 0 {
+		return append(spliced, previous[after:]...)
+	}
+	for _, item := range previous[after:] {
+		item.Position = item.Position.Shifted(edit.Delta, edit.Bytes)
+		spliced = append(spliced, item)
+	}
+	return spliced
+}
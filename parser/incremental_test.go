@@ -0,0 +1,212 @@
+package parser_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/h0rzn/dbml-lsp/parser"
+	explicitparser "github.com/h0rzn/dbml-lsp/parser/explicit_parser"
+	"github.com/h0rzn/dbml-lsp/parser/strategy"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
+
+// generateSchema returns tables of ten columns, each referencing
+// the previous table, until the schema has at least lines lines
+func generateSchema(lines int) string {
+	var builder strings.Builder
+	for i, count := 0, 0; count < lines; i++ {
+		fmt.Fprintf(&builder, "Table t%d {\n", i)
+		builder.WriteString("  id integer [pk]\n")
+		builder.WriteString("  name varchar\n")
+		for column := range 8 {
+			fmt.Fprintf(&builder, "  c%d integer\n", column)
+		}
+		builder.WriteString("}\n\n")
+		count += 13
+		if i > 0 {
+			fmt.Fprintf(&builder, "Ref: t%d.c0 > t%d.id\n\n", i, i-1)
+			count += 2
+		}
+	}
+	return builder.String()
+}
+
+// replaceLines replaces the lines start to end (exclusive) of text
+// and returns the new text and the edit as the server computes it
+func replaceLines(text string, start int, end int, replacement string) (string, parser.Edit) {
+	lines := strings.SplitAfter(text, "\n")
+	from := len(strings.Join(lines[:start], ""))
+	to := len(strings.Join(lines[:end], ""))
+	return replaceRange(text, from, to, replacement)
+}
+
+// replaceRange replaces the bytes from to to (exclusive) of text
+func replaceRange(text string, from int, to int, replacement string) (string, parser.Edit) {
+	start := strings.Count(text[:from], "\n")
+	end := start + strings.Count(text[from:to], "\n")
+	edit := parser.Edit{
+		Start: uint32(start),
+		End:   uint32(end),
+		Delta: strings.Count(replacement, "\n") - (end - start),
+		Bytes: len(replacement) - (to - from),
+	}
+	return text[:from] + replacement + text[to:], edit
+}
+
+// withoutEOF drops the trailing EOF token, which the full parse
+// scans and the incremental reparse does not splice in
+func withoutEOF(items []tokens.Item) []tokens.Item {
+	filtered := make([]tokens.Item, 0, len(items))
+	for _, item := range items {
+		if item.Token != tokens.EOF {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func TestReparseMatchesParseText(t *testing.T) {
+	// table t0 is on lines 0 to 10, t1 on 12 to 22 followed
+	// by its ref on line 24, t2 on 26 to 36 and its ref on 38
+	text := generateSchema(100)
+	tests := []struct {
+		name        string
+		start, end  int
+		replacement string
+	}{
+		{"rename column", 2, 3, "  title varchar\n"},
+		{"insert column", 3, 3, "  extra integer [not null]\n"},
+		{"delete column", 4, 5, ""},
+		{"change ref", 24, 25, "Ref: t1.c1 > t0.id\n"},
+		{"insert table", 26, 26, "Table inserted {\n  id integer\n}\n\n"},
+		{"delete table", 26, 40, ""},
+		{"join lines", 13, 15, "  id integer [pk, note: 'joined']\n"},
+		{"stray brace", 25, 25, "}\n"},
+		{"stray brace in table", 5, 5, "}\n"},
+		{"delete closing brace", 22, 23, ""},
+		{"half typed table", 26, 26, "Table half {\n  id integer\n"},
+		{"half typed ref", 24, 25, "Ref: t1.c0 >\n"},
+		{"long form ref", 24, 25, "Ref long {\n  t1.c0 > t0.id\n}\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			incremental := parser.NewParser(explicitparser.NewParser())
+			previous, err := incremental.ParseText(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			edited, edit := replaceLines(text, test.start, test.end, test.replacement)
+			reparsed, reparseErr := incremental.Reparse(previous, edited, edit)
+			parsed, parseErr := parser.NewParser(explicitparser.NewParser()).ParseText(edited)
+			if (reparseErr == nil) != (parseErr == nil) {
+				t.Fatalf("reparse error %v, parse error %v", reparseErr, parseErr)
+			}
+			assertSameResult(t, reparsed, parsed)
+		})
+	}
+}
+
+// TestReparseRandomEdits types random fragments into a schema. Edits
+// leaving the schema valid are kept, the others are undone.
+func TestReparseRandomEdits(t *testing.T) {
+	fragments := []string{"", "", "}", "{", "\n", "}\n", "[", "]", "'", ":", ".", " ", "pk", "Table x {", "Ref: t1.c0 > t0.id\n"}
+	random := rand.New(rand.NewSource(1))
+	base := generateSchema(150)
+	incremental := parser.NewParser(explicitparser.NewParser())
+	basePrevious, err := incremental.ParseText(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, previous := base, basePrevious
+	for i := 0; i < 2000; i++ {
+		from := random.Intn(len(text) + 1)
+		to := min(len(text), from+random.Intn(4))
+		replacement := fragments[random.Intn(len(fragments))]
+		edited, edit := replaceRange(text, from, to, replacement)
+
+		reparsed, reparseErr := incremental.Reparse(previous, edited, edit)
+		parsed, parseErr := parser.NewParser(explicitparser.NewParser()).ParseText(edited)
+		if (reparseErr == nil) != (parseErr == nil) {
+			t.Fatalf("edit %d replacing %q at %d with %q: reparse error %v, parse error %v", i, text[from:to], from, replacement, reparseErr, parseErr)
+		}
+		assertSameResult(t, reparsed, parsed)
+		if t.Failed() {
+			t.Fatalf("edit %d replacing %q at %d with %q", i, text[from:to], from, replacement)
+		}
+
+		text, previous = edited, reparsed
+		if parseErr != nil {
+			text, previous = base, basePrevious
+		}
+	}
+
+	// symbols are shared between the results, reparsing must not modify them
+	parsed, err := parser.NewParser(explicitparser.NewParser()).ParseText(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameResult(t, basePrevious, parsed)
+}
+
+func assertSameResult(t *testing.T, reparsed *strategy.Result, parsed *strategy.Result) {
+	t.Helper()
+	if !reflect.DeepEqual(reparsed.Symbols.Tables(), parsed.Symbols.Tables()) {
+		t.Error("tables differ")
+	}
+	if !reflect.DeepEqual(reparsed.Symbols.Relationships(), parsed.Symbols.Relationships()) {
+		t.Error("relationships differ")
+	}
+	if !reflect.DeepEqual(reparsed.Symbols.Enums(), parsed.Symbols.Enums()) {
+		t.Error("enums differ")
+	}
+	if !reflect.DeepEqual(reparsed.Symbols.Notes(), parsed.Symbols.Notes()) {
+		t.Error("notes differ")
+	}
+	if !reflect.DeepEqual(reparsed.Symbols.TableGroups(), parsed.Symbols.TableGroups()) {
+		t.Error("table groups differ")
+	}
+	if !reflect.DeepEqual(withoutEOF(reparsed.Tokens), withoutEOF(parsed.Tokens)) {
+		t.Error("tokens differ")
+	}
+	if !reflect.DeepEqual(reparsed.Diagnostics, parsed.Diagnostics) {
+		t.Errorf("diagnostics differ: %v and %v", reparsed.Diagnostics, parsed.Diagnostics)
+	}
+}
+
+func BenchmarkFullParse(b *testing.B) {
+	textParser := parser.NewParser(explicitparser.NewParser())
+	text := generateSchema(10000)
+	b.ResetTimer()
+	for range b.N {
+		if _, err := textParser.ParseText(text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReparse renames a column of a table in
+// the middle of the schema parsed in BenchmarkFullParse
+func BenchmarkReparse(b *testing.B) {
+	textParser := parser.NewParser(explicitparser.NewParser())
+	text := generateSchema(10000)
+	previous, err := textParser.ParseText(text)
+	if err != nil {
+		b.Fatal(err)
+	}
+	lines := strings.Split(text, "\n")
+	line := len(lines) / 2
+	for lines[line] != "  name varchar" {
+		line++
+	}
+	edited, edit := replaceLines(text, line, line+1, "  title varchar\n")
+	b.ResetTimer()
+	for range b.N {
+		if _, err := textParser.Reparse(previous, edited, edit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
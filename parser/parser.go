@@ -1,23 +1,56 @@
 package parser
 
 import (
+	"io"
+	"strings"
+	"sync/atomic"
+
 	"github.com/h0rzn/dbml-lsp/parser/strategy"
 	"github.com/h0rzn/dbml-lsp/parser/symbols"
 )
 
+// Parser runs a parsing strategy and publishes the
+// symbols of every successful run as a new snapshot.
 type Parser struct {
-	strategy.Strategy
-	Symbols *symbols.Storage
+	strategy strategy.Strategy
+	snapshot atomic.Pointer[symbols.Snapshot]
+	version  atomic.Uint64
 }
 
 func NewParser(strategy strategy.Strategy) *Parser {
-	return &Parser{
-		strategy,
-		symbols.NewStorage(),
+	parser := &Parser{
+		strategy: strategy,
 	}
+	parser.snapshot.Store(symbols.NewSnapshot(0, symbols.NewStorage()))
+	return parser
+}
+
+// Parse parses the document read from reader into a fresh result.
+// The symbols of the result are frozen and, if parsing succeeded,
+// published as new snapshot. On error the previous snapshot stays in place.
+func (p *Parser) Parse(reader io.Reader) (*strategy.Result, error) {
+	result, err := p.strategy.Parse(reader)
+	if err != nil {
+		result.Symbols.Freeze()
+		return result, err
+	}
+
+	p.publish(result)
+	return result, nil
+}
+
+// publish freezes the symbols of result and stores them as new snapshot
+func (p *Parser) publish(result *strategy.Result) {
+	p.snapshot.Store(symbols.NewSnapshot(p.version.Add(1), result.Symbols))
+}
+
+// ParseText parses the document text, see Parse
+func (p *Parser) ParseText(text string) (*strategy.Result, error) {
+	return p.Parse(strings.NewReader(text))
 }
 
-func (p *Parser) Init() error {
-	p.SetSymbols(p.Symbols)
-	return nil
+// Snapshot returns the latest published snapshot,
+// safe to use concurrently with Parse.
+func (p *Parser) Snapshot() *symbols.Snapshot {
+	return p.snapshot.Load()
 }
@@ -0,0 +1,55 @@
+package strategy
+
+import (
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/binder"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// Block is a top-level statement together with the lines it spans
+type Block struct {
+	Start     uint32
+	End       uint32
+	Statement ast.Statement
+	// symbol is bound from Statement once, blocks taken
+	// over by a reparse are declared without binding again
+	symbol any
+}
+
+// NewBlock returns the block of statement spanning lines start to end
+func NewBlock(start uint32, end uint32, statement ast.Statement) *Block {
+	return &Block{
+		Start:     start,
+		End:       end,
+		Statement: statement,
+		symbol:    binder.BindStatement(statement),
+	}
+}
+
+// Intersects reports whether the block spans any line from start to end
+func (b *Block) Intersects(start uint32, end uint32) bool {
+	return b.Start <= end && b.End >= start
+}
+
+// Declare adds the symbols of the statement to storage
+func (b *Block) Declare(storage *symbols.Storage) error {
+	return binder.DeclareSymbol(storage, b.symbol)
+}
+
+// Shifted returns a copy of the block moved by delta lines and bytes bytes
+func (b *Block) Shifted(delta int, bytes int) *Block {
+	return NewBlock(uint32(int(b.Start)+delta), uint32(int(b.End)+delta), ast.Shifted(b.Statement, delta, bytes))
+}
+
+// File returns the syntax tree made of the statements of blocks
+func File(blocks []*Block) *ast.File {
+	file := &ast.File{Statements: make([]ast.Statement, 0, len(blocks))}
+	for _, block := range blocks {
+		file.Statements = append(file.Statements, block.Statement)
+	}
+	if len(file.Statements) > 0 {
+		file.From = file.Statements[0].Start()
+		file.To = file.Statements[len(file.Statements)-1].End()
+	}
+	return file
+}
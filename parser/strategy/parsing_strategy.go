@@ -1,10 +1,41 @@
 package strategy
 
-import "github.com/h0rzn/dbml-lsp/parser/symbols"
+import (
+	"io"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/ast"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+)
 
 // Strategy Inferface
-// for different parser systems
+// for different parser systems.
+// Every call to Parse starts from scratch,
+// no state is shared between calls.
 type Strategy interface {
-	Parse() error
-	SetSymbols(*symbols.Storage)
+	Parse(reader io.Reader) (*Result, error)
+}
+
+// FragmentStrategy is implemented by strategies that can
+// parse a fragment of a document made of whole top-level blocks.
+// The fragment starts at line, which starts at byte offset in
+// the document. Positions of the result are relative to the document.
+type FragmentStrategy interface {
+	Strategy
+	ParseFragment(reader io.Reader, line uint32, offset uint32) (*Result, error)
+}
+
+// Result is the outcome of one parse run
+type Result struct {
+	// File is the syntax tree of the parsed statements
+	File *ast.File
+	// Symbols are bound from File
+	Symbols *symbols.Storage
+	// Diagnostics holds the parse errors with their position
+	Diagnostics []*analysis.Diagnostic
+	// Tokens are the scanned items in order of appearance
+	Tokens []tokens.Item
+	// Blocks are the top-level declarations in order of appearance
+	Blocks []*Block
 }
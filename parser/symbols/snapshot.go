@@ -0,0 +1,18 @@
+package symbols
+
+// Snapshot is the result of one completed parse run.
+// Its storage is frozen, so it can be read concurrently
+// while the next version is being built.
+type Snapshot struct {
+	// Version increases with every published snapshot
+	Version uint64
+	Storage *Storage
+}
+
+func NewSnapshot(version uint64, storage *Storage) *Snapshot {
+	storage.Freeze()
+	return &Snapshot{
+		Version: version,
+		Storage: storage,
+	}
+}
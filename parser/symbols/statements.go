@@ -3,22 +3,91 @@ package symbols
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/h0rzn/dbml-lsp/parser/tokens"
 )
 
 type Project struct {
-	Options  map[string]string
-	Name     string
+	Options map[string]string
+	// OptionPositions holds the position of each option key
+	OptionPositions map[string]tokens.Position
+	Name            string
+	// Note is Markdown
+	Note     string
+	Position tokens.Position
+}
+
+// database types of the project option 'database_type'
+const (
+	PostgreSQL = "PostgreSQL"
+	MySQL      = "MySQL"
+	SQLite     = "SQLite"
+	SQLServer  = "SQL Server"
+	Oracle     = "Oracle"
+)
+
+// DatabaseTypes are the database types known to dbml
+var DatabaseTypes = []string{PostgreSQL, MySQL, SQLite, SQLServer, Oracle}
+
+// DatabaseType returns the database type declared with 'database_type'
+// as one of DatabaseTypes, false if it is missing or unknown
+func (p *Project) DatabaseType() (string, bool) {
+	return NormalizeDatabaseType(p.Options["database_type"])
+}
+
+// NormalizeDatabaseType maps names like "postgres" or
+// "mssql" to one of DatabaseTypes
+func NormalizeDatabaseType(databaseType string) (string, bool) {
+	switch strings.ToLower(strings.ReplaceAll(databaseType, " ", "")) {
+	case "postgresql", "postgres", "pg":
+		return PostgreSQL, true
+	case "mysql", "mariadb":
+		return MySQL, true
+	case "sqlite", "sqlite3":
+		return SQLite, true
+	case "sqlserver", "mssql":
+		return SQLServer, true
+	case "oracle":
+		return Oracle, true
+	}
+	return databaseType, false
+}
+
+// Note is a sticky note 'Note name { ... }'
+type Note struct {
+	Name string
+	// Content is Markdown
+	Content  string
 	Position tokens.Position
+	// End is the position of the closing brace
+	End tokens.Position
+}
+
+// DefaultScheme is the scheme of tables declared without one
+const DefaultScheme = "public"
+
+// QualifiedName returns scheme.name, using the default scheme if scheme is empty
+func QualifiedName(scheme string, name string) string {
+	if scheme == "" {
+		scheme = DefaultScheme
+	}
+	return scheme + "." + name
 }
 
 type Table struct {
 	Scheme     string
 	Name       string
+	Alias      string
 	Columns    []*Column
+	Indexes    []*Index
 	References []*Relationship
-	Position   tokens.Position
+	Note       string
+	// HeaderColor is the headercolor setting like #3498DB
+	HeaderColor string
+	Position    tokens.Position
+	// End is the position of the closing brace
+	End tokens.Position
 }
 
 func (t *Table) String() string {
@@ -40,6 +109,33 @@ func (t *Table) Print() {
 
 }
 
+func (t *Table) QualifiedName() string {
+	return QualifiedName(t.Scheme, t.Name)
+}
+
+func (t *Table) SchemeOrDefault() string {
+	if t.Scheme == "" {
+		return DefaultScheme
+	}
+	return t.Scheme
+}
+
+// Encloses reports whether line:offset is inside the table definition
+func (t *Table) Encloses(line uint32, offset uint32) bool {
+	afterStart := line > t.Position.Line || (line == t.Position.Line && offset >= t.Position.Offset)
+	beforeEnd := line < t.End.Line || (line == t.End.Line && offset <= t.End.Offset)
+	return afterStart && beforeEnd
+}
+
+func (t *Table) ColumnByName(name string) (*Column, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return nil, false
+}
+
 type Column struct {
 	Name        string
 	Type        string
@@ -61,6 +157,93 @@ func (c *Column) String() string {
 	return out
 }
 
+// Setting returns the value of the keyed setting like 'default'
+func (c *Column) Setting(key string) (string, bool) {
+	for _, constraint := range c.Constraints {
+		if constraint.Key == key {
+			return constraint.Value, true
+		}
+	}
+	return "", false
+}
+
+// Has reports whether the column has the setting
+// without key like 'unique' or 'not null'
+func (c *Column) Has(flag string) bool {
+	for _, constraint := range c.Constraints {
+		if constraint.Key == "" && constraint.Value == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrimaryKey reports whether the column is declared 'pk' or 'primary key'
+func (c *Column) IsPrimaryKey() bool {
+	return c.Has("pk") || c.Has("primary key")
+}
+
+// Index is an entry of the indexes block of a table
+type Index struct {
+	// Columns are column names or expressions in backticks
+	Columns    []string
+	PrimaryKey bool
+	Unique     bool
+	Name       string
+	// Type is the index method like 'btree' or 'hash'
+	Type     string
+	Note     string
+	Position tokens.Position
+}
+
+// Enum is 'Enum scheme.name { values }'
+type Enum struct {
+	Scheme   string
+	Name     string
+	Values   []*EnumValue
+	Position tokens.Position
+	// End is the position of the closing brace
+	End tokens.Position
+}
+
+func (e *Enum) QualifiedName() string {
+	return QualifiedName(e.Scheme, e.Name)
+}
+
+func (e *Enum) SchemeOrDefault() string {
+	if e.Scheme == "" {
+		return DefaultScheme
+	}
+	return e.Scheme
+}
+
+// TableGroup is 'TableGroup name { tables }'
+type TableGroup struct {
+	Name string
+	// Tables are the qualified names of the members
+	Tables []string
+	// Color is the color setting like #3498DB
+	Color    string
+	Position tokens.Position
+	// End is the position of the closing brace
+	End tokens.Position
+}
+
+// Contains reports whether table is a member of the group
+func (g *TableGroup) Contains(table *Table) bool {
+	for _, name := range g.Tables {
+		if name == table.QualifiedName() {
+			return true
+		}
+	}
+	return false
+}
+
+type EnumValue struct {
+	Name string
+	Note string
+}
+
 type Constraint struct {
 	// Only for 'key: value' constraints
 	// empty string otherwise
@@ -78,6 +261,9 @@ type Relationship struct {
 	TableB  string
 	ColumnB string
 	Type    string
+	// Inline is set for refs declared as column setting
+	Inline   bool
+	Position tokens.Position
 }
 
 func (r *Relationship) String() string {
@@ -91,3 +277,23 @@ type ReferenceTo struct {
 	RefColumn string
 	Type      string
 }
+
+// Import is a use or reuse statement, e.g.
+// use { table auth.users as u } from './auth.dbml'
+type Import struct {
+	// Reuse imports re-export the imported symbols
+	Reuse bool
+	// All is set for 'use * from'
+	All      bool
+	Items    []*ImportItem
+	Path     string
+	Position tokens.Position
+}
+
+type ImportItem struct {
+	// Kind of the imported symbol like "table" or "enum"
+	Kind   string
+	Scheme string
+	Name   string
+	Alias  string
+}
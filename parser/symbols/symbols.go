@@ -2,99 +2,444 @@ package symbols
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 )
 
 type Storage struct {
-	*sync.Mutex
-	project *Project
-	tables  map[uint32]*Table
+	mutex sync.RWMutex
+	// frozen storages are published as snapshot and must not change
+	frozen bool
+	// projects in order of declaration, only
+	// one project per document is valid
+	projects []*Project
+	tables   *tableIndex
+	imports  []*Import
+	notes    []*Note
+	enums    []*Enum
+	groups   []*TableGroup
+	// top-level refs whose host table is
+	// not declared in this file (e.g. imported)
+	relationships []*Relationship
 }
 
 func NewStorage() *Storage {
 	return &Storage{
-		&sync.Mutex{},
-		&Project{},
-		make(map[uint32]*Table),
+		projects:      make([]*Project, 0),
+		tables:        newTableIndex(),
+		imports:       make([]*Import, 0),
+		notes:         make([]*Note, 0),
+		enums:         make([]*Enum, 0),
+		groups:        make([]*TableGroup, 0),
+		relationships: make([]*Relationship, 0),
+	}
+}
+
+// Freeze makes the storage read-only,
+// further mutations panic.
+func (s *Storage) Freeze() {
+	s.mutex.Lock()
+	s.frozen = true
+	s.mutex.Unlock()
+}
+
+// lock acquires the write lock of a storage that is not frozen
+func (s *Storage) lock() {
+	s.mutex.Lock()
+	if s.frozen {
+		s.mutex.Unlock()
+		panic("symbols: mutation of frozen storage")
 	}
 }
 
 // Project
-func (s *Storage) SetProject(project *Project) {
-	s.project = project
+func (s *Storage) AddProject(project *Project) {
+	s.lock()
+	s.projects = append(s.projects, project)
+	s.mutex.Unlock()
 }
 
+// GetProject returns the first declared project,
+// nil if the document has no project definition
 func (s *Storage) GetProject() *Project {
-	return s.project
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if len(s.projects) == 0 {
+		return nil
+	}
+	return s.projects[0]
+}
+
+// Projects returns all declared projects
+func (s *Storage) Projects() []*Project {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*Project{}, s.projects...)
 }
 
 // Table
+
+// TableByName looks up a table by its qualified name (scheme.table),
+// its alias or its plain name. Plain names resolve to the default
+// scheme first, then to the only table of that name in any scheme.
 func (s *Storage) TableByName(name string) (*Table, bool) {
-	// table, exists := s.tables[name]
-	for _, table := range s.tables {
-		if table.Name == name {
-			return table, true
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.tables.lookup(name)
+}
+
+// ResolveTable looks up the table referenced as scheme.name,
+// an empty scheme resolves like a plain name in TableByName.
+func (s *Storage) ResolveTable(scheme string, name string) (*Table, bool) {
+	if scheme != "" {
+		name = QualifiedName(scheme, name)
+	}
+	return s.TableByName(name)
+}
+
+// TableByAlias looks up a table by the alias declared with 'as'
+func (s *Storage) TableByAlias(alias string) (*Table, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	qualified, exists := s.tables.aliases[alias]
+	if !exists {
+		return nil, false
+	}
+	return s.tables.byName[qualified], true
+}
+
+// Tables returns all tables in order of declaration
+func (s *Storage) Tables() []*Table {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	tables := make([]*Table, 0, len(s.tables.order))
+	for _, qualified := range s.tables.order {
+		tables = append(tables, s.tables.byName[qualified])
+	}
+	return tables
+}
+
+// TablesByScheme returns the tables of scheme in order of declaration
+func (s *Storage) TablesByScheme(scheme string) []*Table {
+	if scheme == "" {
+		scheme = DefaultScheme
+	}
+	tables := make([]*Table, 0)
+	for _, table := range s.Tables() {
+		if table.SchemeOrDefault() == scheme {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// Schemes returns the schemes in order of first use
+func (s *Storage) Schemes() []string {
+	schemes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, table := range s.Tables() {
+		scheme := table.SchemeOrDefault()
+		if !seen[scheme] {
+			seen[scheme] = true
+			schemes = append(schemes, scheme)
 		}
 	}
+	return schemes
+}
 
+// TableAt returns the table whose definition encloses line:offset
+func (s *Storage) TableAt(line uint32, offset uint32) (*Table, bool) {
+	for _, table := range s.Tables() {
+		if table.Encloses(line, offset) {
+			return table, true
+		}
+	}
 	return nil, false
 }
 
-func (s *Storage) Tables() map[uint32]*Table {
-	return s.tables
+// ColumnAt returns the column defined in line and its table
+func (s *Storage) ColumnAt(line uint32, offset uint32) (*Table, *Column, bool) {
+	table, exists := s.TableAt(line, offset)
+	if !exists {
+		return nil, nil, false
+	}
+	for _, column := range table.Columns {
+		if column.Position.Line == line {
+			return table, column, true
+		}
+	}
+	return table, nil, false
 }
 
+// PutTable adds table or replaces the table with the same qualified name
+// PutTable adds or replaces table, refs added before
+// it was declared are attached to its references
 func (s *Storage) PutTable(table *Table) {
-	s.Lock()
-	s.tables[table.Position.Line] = table
-	s.Unlock()
+	s.lock()
+	s.tables.put(table)
+	s.attachRelationships(table)
+	s.mutex.Unlock()
+}
+
+// attachRelationships moves the refs hosted by table
+// from the unattached relationships to its references
+func (s *Storage) attachRelationships(table *Table) {
+	unattached := make([]*Relationship, 0, len(s.relationships))
+	for _, rel := range s.relationships {
+		name := rel.TableA
+		if rel.SchemeA != "" {
+			name = QualifiedName(rel.SchemeA, rel.TableA)
+		}
+		if host, exists := s.tables.lookup(name); exists && host == table {
+			table.References = append(table.References, rel)
+			continue
+		}
+		unattached = append(unattached, rel)
+	}
+	s.relationships = unattached
 }
 
 func (s *Storage) UpdateTable(tableName string, updatedTable *Table) error {
-	_, exists := s.TableByName(tableName)
-	if !exists {
+	s.lock()
+	defer s.mutex.Unlock()
+	if _, exists := s.tables.lookup(tableName); !exists {
 		return fmt.Errorf("failed to find table %q", tableName)
 	}
-	s.PutTable(updatedTable)
+	s.tables.put(updatedTable)
 
 	return nil
 }
 
 func (s *Storage) DropTableByName(name string) {
-	s.Lock()
-	var line uint32
-	var found bool
-
-	for l, table := range s.tables {
-		if table.Name == name {
-			line = l
-			found = true
+	s.lock()
+	if table, exists := s.tables.lookup(name); exists {
+		s.tables.drop(table)
+	}
+	s.mutex.Unlock()
+}
+
+// Import
+func (s *Storage) AddImport(imp *Import) {
+	s.lock()
+	s.imports = append(s.imports, imp)
+	s.mutex.Unlock()
+}
+
+func (s *Storage) Imports() []*Import {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*Import{}, s.imports...)
+}
+
+// Note
+func (s *Storage) AddNote(note *Note) {
+	s.lock()
+	s.notes = append(s.notes, note)
+	s.mutex.Unlock()
+}
+
+func (s *Storage) Notes() []*Note {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*Note{}, s.notes...)
+}
+
+func (s *Storage) NoteByName(name string) (*Note, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, note := range s.notes {
+		if note.Name == name {
+			return note, true
 		}
 	}
-	if found {
-		delete(s.tables, line)
+	return nil, false
+}
+
+// Enum
+func (s *Storage) AddEnum(enum *Enum) {
+	s.lock()
+	s.enums = append(s.enums, enum)
+	s.mutex.Unlock()
+}
+
+func (s *Storage) Enums() []*Enum {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*Enum{}, s.enums...)
+}
+
+// EnumByName looks up an enum by its qualified name (scheme.enum)
+// or its plain name, like column types refer to enums
+func (s *Storage) EnumByName(name string) (*Enum, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, enum := range s.enums {
+		if enum.QualifiedName() == name || (enum.Name == name && enum.SchemeOrDefault() == DefaultScheme) {
+			return enum, true
+		}
+	}
+	for _, enum := range s.enums {
+		if enum.Name == name {
+			return enum, true
+		}
 	}
-	s.Unlock()
+	return nil, false
+}
+
+// TableGroup
+func (s *Storage) AddTableGroup(group *TableGroup) {
+	s.lock()
+	s.groups = append(s.groups, group)
+	s.mutex.Unlock()
+}
+
+func (s *Storage) TableGroups() []*TableGroup {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*TableGroup{}, s.groups...)
+}
+
+func (s *Storage) TableGroupByName(name string) (*TableGroup, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, group := range s.groups {
+		if group.Name == name {
+			return group, true
+		}
+	}
+	return nil, false
+}
+
+// GroupOf returns the first group listing table
+func (s *Storage) GroupOf(table *Table) (*TableGroup, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, group := range s.groups {
+		if group.Contains(table) {
+			return group, true
+		}
+	}
+	return nil, false
+}
+
+// Relationship
+func (s *Storage) AddRelationship(rel *Relationship) {
+	s.lock()
+	s.relationships = append(s.relationships, rel)
+	s.mutex.Unlock()
+}
+
+// Relationships returns refs not attached to a table of this storage
+func (s *Storage) Relationships() []*Relationship {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*Relationship{}, s.relationships...)
 }
 
 // Column
 func (s *Storage) ColumnsByTableName(name string) []*Column {
-	s.Lock()
-	columns := make([]*Column, 0)
-	if table, exists := s.TableByName(name); exists {
-		columns = table.Columns
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if table, exists := s.tables.lookup(name); exists {
+		return table.Columns
 	}
-	s.Unlock()
-	return columns
+	return make([]*Column, 0)
 }
 
 // Misc
 func (s *Storage) Clear() {
-	s.Lock()
-	clear(s.tables)
-	s.Unlock()
+	s.lock()
+	s.projects = s.projects[:0]
+	s.tables = newTableIndex()
+	s.imports = s.imports[:0]
+	s.notes = s.notes[:0]
+	s.enums = s.enums[:0]
+	s.groups = s.groups[:0]
+	s.relationships = s.relationships[:0]
+	s.mutex.Unlock()
 }
 
 func (s *Storage) Info() string {
-	return fmt.Sprintf("Symbol Storage: [project defined: %t], %d Tables", s.project != nil, len(s.tables))
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return fmt.Sprintf("Symbol Storage: [project defined: %t], %d Tables", len(s.projects) > 0, len(s.tables.order))
+}
+
+// tableIndex keys tables by qualified name and keeps
+// secondary lookups by alias and plain name.
+type tableIndex struct {
+	byName map[string]*Table
+	// qualified names in order of declaration
+	order   []string
+	aliases map[string]string
+	// plain name to qualified names
+	plain map[string][]string
+}
+
+func newTableIndex() *tableIndex {
+	return &tableIndex{
+		byName:  make(map[string]*Table),
+		order:   make([]string, 0),
+		aliases: make(map[string]string),
+		plain:   make(map[string][]string),
+	}
+}
+
+func (i *tableIndex) put(table *Table) {
+	qualified := table.QualifiedName()
+	if previous, exists := i.byName[qualified]; exists {
+		// replace in place to keep the order of declaration
+		if previous.Alias != "" && i.aliases[previous.Alias] == qualified {
+			delete(i.aliases, previous.Alias)
+		}
+	} else {
+		i.order = append(i.order, qualified)
+		i.plain[table.Name] = append(i.plain[table.Name], qualified)
+	}
+	i.byName[qualified] = table
+	if table.Alias != "" {
+		i.aliases[table.Alias] = qualified
+	}
+}
+
+func (i *tableIndex) drop(table *Table) {
+	qualified := table.QualifiedName()
+	delete(i.byName, qualified)
+	i.order = without(i.order, qualified)
+	i.plain[table.Name] = without(i.plain[table.Name], qualified)
+	if len(i.plain[table.Name]) == 0 {
+		delete(i.plain, table.Name)
+	}
+	if table.Alias != "" && i.aliases[table.Alias] == qualified {
+		delete(i.aliases, table.Alias)
+	}
+}
+
+func (i *tableIndex) lookup(name string) (*Table, bool) {
+	if table, exists := i.byName[name]; exists {
+		return table, true
+	}
+	if strings.Contains(name, ".") {
+		return nil, false
+	}
+	if table, exists := i.byName[QualifiedName(DefaultScheme, name)]; exists {
+		return table, true
+	}
+	if qualified, exists := i.aliases[name]; exists {
+		return i.byName[qualified], true
+	}
+	if candidates := i.plain[name]; len(candidates) == 1 {
+		return i.byName[candidates[0]], true
+	}
+	return nil, false
+}
+
+func without(values []string, value string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
 }
@@ -0,0 +1,88 @@
+//go:build ignore
+
+// gen writes token_string.go from the token declarations in tokens.go.
+// The trailing comment of a token is its display name: tokens in the
+// meta and literal sections are described by the whole comment, all
+// other tokens print the literal (first word of the comment) in quotes.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "tokens.go", nil, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var names []string
+	var display []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			name := value.Names[0].Name
+			if name == "tokenCount" {
+				continue
+			}
+			names = append(names, name)
+			display = append(display, displayName(name, value.Comment, len(names) <= metaTokens(gen)))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\npackage tokens\n\n")
+	buf.WriteString("var tokenNames = [...]string{\n")
+	for i, name := range names {
+		fmt.Fprintf(&buf, "\t%s: %s,\n", name, strconv.Quote(display[i]))
+	}
+	buf.WriteString("}\n\n")
+	buf.WriteString("func (t Token) String() string {\n")
+	buf.WriteString("\tif int(t) < len(tokenNames) {\n\t\treturn tokenNames[t]\n\t}\n")
+	buf.WriteString("\treturn \"Token(\" + strconv.Itoa(int(t)) + \")\"\n}\n")
+
+	source := strings.Replace(buf.String(), "package tokens\n", "package tokens\n\nimport \"strconv\"\n", 1)
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("token_string.go", formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// metaTokens counts the tokens before the keyword section
+func metaTokens(gen *ast.GenDecl) int {
+	for i, spec := range gen.Specs {
+		value := spec.(*ast.ValueSpec)
+		if value.Doc != nil && strings.Contains(value.Doc.Text(), "keywords") {
+			return i
+		}
+	}
+	return 0
+}
+
+func displayName(name string, comment *ast.CommentGroup, meta bool) string {
+	if comment == nil {
+		return name
+	}
+	text := strings.TrimSpace(comment.Text())
+	if meta {
+		return text
+	}
+	return "'" + strings.Fields(text)[0] + "'"
+}
@@ -0,0 +1,8 @@
+package tokens
+
+// Item is a scanned token with its literal value and position
+type Item struct {
+	Token    Token
+	Value    string
+	Position Position
+}
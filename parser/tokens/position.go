@@ -1,13 +1,68 @@
 package tokens
 
-import "fmt"
+import (
+	"fmt"
+)
 
+// Position is the range of an item in the document.
+// Columns are counted in UTF-16 code units, the
+// default position encoding of LSP clients.
 type Position struct {
-	Line   uint32
+	Line uint32
+	// Offset is the column of the first character
 	Offset uint32
-	Len    uint32
+	// Len is the length of the item on its first line
+	Len uint32
+	// Byte is the byte offset of the item in the document
+	Byte uint32
+	// EndLine and EndOffset are only set for items
+	// spanning several lines, see End
+	EndLine   uint32
+	EndOffset uint32
 }
 
 func (p *Position) String() string {
-	return fmt.Sprintf("[%d:%d-%d]", p.Line, p.Offset, p.Offset+p.Len)
+	line, offset := p.End()
+	if line != p.Line {
+		return fmt.Sprintf("[%d:%d-%d:%d]", p.Line, p.Offset, line, offset)
+	}
+	return fmt.Sprintf("[%d:%d-%d]", p.Line, p.Offset, offset)
+}
+
+// End returns line and column after the last character
+func (p *Position) End() (line uint32, offset uint32) {
+	if p.EndLine > p.Line {
+		return p.EndLine, p.EndOffset
+	}
+	return p.Line, p.Offset + p.Len
+}
+
+// Shifted returns the position moved by
+// delta lines and bytes bytes
+func (p *Position) Shifted(delta int, bytes int) Position {
+	shifted := *p
+	shifted.Line = uint32(int(p.Line) + delta)
+	shifted.Byte = uint32(int(p.Byte) + bytes)
+	if p.EndLine > p.Line {
+		shifted.EndLine = uint32(int(p.EndLine) + delta)
+	}
+	return shifted
+}
+
+// UTF16Len returns the length of text in UTF-16 code units
+func UTF16Len(text string) uint32 {
+	var length uint32
+	for _, char := range text {
+		length += UTF16RuneLen(char)
+	}
+	return length
+}
+
+// UTF16RuneLen returns the number of UTF-16 code units of char,
+// two for characters outside of the basic multilingual plane
+func UTF16RuneLen(char rune) uint32 {
+	if char >= 0x10000 {
+		return 2
+	}
+	return 1
 }
@@ -0,0 +1,48 @@
+package tokens
+
+import "strings"
+
+const setWords = (int(tokenCount) + 63) / 64
+
+// Set is a set of tokens, membership tests are a single bit test
+type Set [setWords]uint64
+
+func NewSet(tokens ...Token) Set {
+	var set Set
+	for _, token := range tokens {
+		set[token/64] |= 1 << (token % 64)
+	}
+	return set
+}
+
+// Has reports whether token is in the set
+func (s Set) Has(token Token) bool {
+	return s[token/64]&(1<<(token%64)) != 0
+}
+
+// Union returns a set with the tokens of s and other
+func (s Set) Union(other Set) Set {
+	for i := range s {
+		s[i] |= other[i]
+	}
+	return s
+}
+
+// Tokens returns the tokens of the set in order
+func (s Set) Tokens() []Token {
+	var tokens []Token
+	for token := Token(0); token < tokenCount; token++ {
+		if s.Has(token) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func (s Set) String() string {
+	names := make([]string, 0)
+	for _, token := range s.Tokens() {
+		names = append(names, token.String())
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,66 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package tokens
+
+import "strconv"
+
+var tokenNames = [...]string{
+	IDENT:                 "identifier",
+	WHITESPACE:            "whitespace",
+	LINEBR:                "line break",
+	EOF:                   "end of file",
+	ILLEGAL:               "illegal token",
+	UNKOWN:                "unkown token",
+	COL_SETTING_CUSTOM:    "custom column type",
+	NUMBER:                "number",
+	STRING:                "string",
+	COLOR:                 "color",
+	BOOLEAN:               "boolean",
+	EXPRESSION:            "expression",
+	PROJECT:               "'Project'",
+	PROJECT_DATABASE_TYPE: "'database_type'",
+	NOTE_CAP:              "'Note'",
+	TABLE:                 "'Table'",
+	ENUM:                  "'Enum'",
+	TABLEGROUP:            "'TableGroup'",
+	INDEXES:               "'indexes'",
+	REF_CAP:               "'Ref'",
+	REF_LOW:               "'ref'",
+	CONS_PK:               "'pk'",
+	CONS_PRIMARY:          "'primary'",
+	CONS_KEY:              "'key'",
+	CONS_NULL:             "'null'",
+	CONS_NOT:              "'not'",
+	CONS_INCREMENT:        "'increment'",
+	CONS_UNIQUE:           "'unique'",
+	CONS_DEFAULT:          "'default'",
+	NOTE:                  "'note'",
+	USE:                   "'use'",
+	REUSE:                 "'reuse'",
+	FROM:                  "'from'",
+	AS:                    "'as'",
+	REL_1T1:               "'-'",
+	REL_1TM:               "'<'",
+	REL_MT1:               "'>'",
+	REL_MTN:               "'<>'",
+	SLASH:                 "'/'",
+	BRACE_OPEN:            "'{'",
+	BRACE_CLOSE:           "'}'",
+	SQUARE_OPEN:           "'['",
+	SQUARE_CLOSE:          "']'",
+	ROUND_OPEN:            "'('",
+	ROUND_CLOSE:           "')'",
+	COLON:                 "':'",
+	COMMA:                 "','",
+	APOSTROPHE:            "'''",
+	QUOTATION:             "'\"'",
+	DOT:                   "'.'",
+	ASTERISK:              "'*'",
+}
+
+func (t Token) String() string {
+	if int(t) < len(tokenNames) {
+		return tokenNames[t]
+	}
+	return "Token(" + strconv.Itoa(int(t)) + ")"
+}
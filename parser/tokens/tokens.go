@@ -1,6 +1,10 @@
 package tokens
 
-type Token uint64
+//go:generate go run gen.go
+
+// Token is the kind of a scanned item. Tokens are ordinals,
+// use a Set to test for membership in a group of tokens.
+type Token uint8
 
 var EOFChar = rune(0)
 
@@ -9,22 +13,33 @@ const (
 	//
 	// meta
 	//
-	IDENT      = 1 << iota
-	WHITESPACE // whitespace
-	LINEBR     // line break \n
-	EOF        // end-of-file
-	ILLEGAL    // illegal token
-	UNKOWN     // unkown token
+	IDENT              Token = iota // identifier
+	WHITESPACE                      // whitespace
+	LINEBR                          // line break
+	EOF                             // end of file
+	ILLEGAL                         // illegal token
+	UNKOWN                          // unkown token
+	COL_SETTING_CUSTOM              // custom column type
+
+	//
+	// literals
+	//
+	NUMBER     // number
+	STRING     // string
+	COLOR      // color
+	BOOLEAN    // boolean
+	EXPRESSION // expression
 
 	//
 	// keywords
 	//
 	PROJECT               // Project
 	PROJECT_DATABASE_TYPE // database_type
-	PROJECT_NOTE          // Note (used in project definition)
+	NOTE_CAP              // Note (sticky note, table and project note)
 	TABLE                 // Table
-	ENUM                  // enum
-	COL_SETTING_CUSTOM    // something like id "bigint unsigned" [pk]
+	ENUM                  // Enum
+	TABLEGROUP            // TableGroup
+	INDEXES               // indexes
 	REF_CAP               // Ref
 	REF_LOW               // ref (inline)
 	// keywords: constraints
@@ -34,9 +49,15 @@ const (
 	CONS_NULL      // null
 	CONS_NOT       // not
 	CONS_INCREMENT // increment
-	CONS_UNIQUE
+	CONS_UNIQUE    // unique
+	CONS_DEFAULT   // default
 
-	NOTE
+	NOTE // note (column and table setting)
+
+	USE   // use (import)
+	REUSE // reuse (import and re-export)
+	FROM  // from
+	AS    // as (import alias)
 
 	REL_1T1 // -
 	REL_1TM // <
@@ -57,14 +78,20 @@ const (
 	COMMA        // ,
 	APOSTROPHE   // '
 	// BACKTICK     // `
-	QUOTATION // \"
+	QUOTATION // "
 	DOT       // .
+	ASTERISK  // *
 
-	//
-	// GROUPS
-	//
-	G_RELATION_TYPE = REL_1T1 | REL_MT1 | REL_1TM | REL_MTN
-	G_PROJECT_OPTS  = PROJECT_NOTE | PROJECT_DATABASE_TYPE
+	// tokenCount is the number of tokens, keep it last
+	tokenCount
+)
+
+// token groups
+var (
+	G_LITERAL       = NewSet(NUMBER, COLOR, BOOLEAN, EXPRESSION, CONS_NULL)
+	G_RELATION_TYPE = NewSet(REL_1T1, REL_MT1, REL_1TM, REL_MTN)
+	// G_PROJECT_OPTS are the tokens of project setting keys
+	G_PROJECT_OPTS = NewSet(IDENT, PROJECT_DATABASE_TYPE)
 )
 
 func MapLiteral(literal string) Token {
@@ -72,13 +99,15 @@ func MapLiteral(literal string) Token {
 	case "Project":
 		return PROJECT
 	case "Note":
-		return PROJECT_NOTE
+		return NOTE_CAP
 	case "database_type":
 		return PROJECT_DATABASE_TYPE
 	case "Table":
 		return TABLE
-	case "enum":
+	case "Enum", "enum":
 		return ENUM
+	case "TableGroup":
+		return TABLEGROUP
 	case "pk":
 		return CONS_PK
 	case "primary":
@@ -107,6 +136,29 @@ func MapLiteral(literal string) Token {
 	return IDENT
 }
 
+// MapKeyword maps the contextual keywords. They are scanned as
+// identifiers and only read as keywords where the parser expects
+// them, so that columns or tables can be named 'from' or 'default'.
+func MapKeyword(literal string) Token {
+	switch literal {
+	case "indexes":
+		return INDEXES
+	case "default":
+		return CONS_DEFAULT
+	case "true", "false":
+		return BOOLEAN
+	case "use":
+		return USE
+	case "reuse":
+		return REUSE
+	case "from":
+		return FROM
+	case "as":
+		return AS
+	}
+	return IDENT
+}
+
 func MapChar(char rune) Token {
 	switch char {
 	case '\n':
@@ -125,6 +177,14 @@ func MapChar(char rune) Token {
 		return SQUARE_CLOSE
 	case '"':
 		return QUOTATION
+	case '\'':
+		return APOSTROPHE
+	case '*':
+		return ASTERISK
+	case '(':
+		return ROUND_OPEN
+	case ')':
+		return ROUND_CLOSE
 	case ',':
 		return COMMA
 	case ':':
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/h0rzn/dbml-lsp/parser/tokens"
+	protocol "github.com/tliron/glsp/protocol_3_16"
+)
+
+// position encodings of LSP 3.17,
+// clients not announcing any use UTF-16
+const (
+	encodingUTF8  = "utf-8"
+	encodingUTF16 = "utf-16"
+	encodingUTF32 = "utf-32"
+)
+
+// positionEncoding is negotiated at initialize,
+// tokens.Position columns are always UTF-16
+var positionEncoding = encodingUTF16
+
+// negotiatePositionEncoding picks the first encoding announced by the
+// client in capabilities.general.positionEncodings that is supported.
+// The second return value reports whether the client announced any.
+func negotiatePositionEncoding(params json.RawMessage) (string, bool) {
+	var initialize struct {
+		Capabilities struct {
+			General struct {
+				PositionEncodings []string `json:"positionEncodings"`
+			} `json:"general"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(params, &initialize); err != nil {
+		return encodingUTF16, false
+	}
+
+	encodings := initialize.Capabilities.General.PositionEncodings
+	for _, encoding := range encodings {
+		switch encoding {
+		case encodingUTF8, encodingUTF16, encodingUTF32:
+			return encoding, true
+		}
+	}
+	return encodingUTF16, len(encodings) > 0
+}
+
+// lineMapper converts positions of a document between
+// UTF-16 columns and the negotiated position encoding
+type lineMapper struct {
+	text string
+	// lines holds the byte offset of every line start
+	lines    []int
+	encoding string
+}
+
+func newLineMapper(text string) *lineMapper {
+	mapper := &lineMapper{
+		text:     text,
+		lines:    []int{0},
+		encoding: positionEncoding,
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			mapper.lines = append(mapper.lines, i+1)
+		}
+	}
+	return mapper
+}
+
+// line returns line n without the line break
+func (m *lineMapper) line(n uint32) string {
+	if int(n) >= len(m.lines) {
+		return ""
+	}
+	line := m.text[m.lines[n]:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	return line
+}
+
+// toClient converts an UTF-16 column in line to the client encoding
+func (m *lineMapper) toClient(line uint32, column uint32) uint32 {
+	if m.encoding == encodingUTF16 {
+		return column
+	}
+	index := byteIndex(m.line(line), column, encodingUTF16)
+	return units(m.line(line)[:index], m.encoding)
+}
+
+// fromClient converts a column in the client encoding to UTF-16
+func (m *lineMapper) fromClient(line uint32, column uint32) uint32 {
+	if m.encoding == encodingUTF16 {
+		return column
+	}
+	index := byteIndex(m.line(line), column, m.encoding)
+	return tokens.UTF16Len(m.line(line)[:index])
+}
+
+// index returns the byte offset of a client position in the text
+func (m *lineMapper) index(position protocol.Position) int {
+	if int(position.Line) >= len(m.lines) {
+		return len(m.text)
+	}
+	return m.lines[position.Line] + byteIndex(m.line(position.Line), position.Character, m.encoding)
+}
+
+// toRange converts position to a client range
+func (m *lineMapper) toRange(position tokens.Position) protocol.Range {
+	endLine, endOffset := position.End()
+	return protocol.Range{
+		Start: m.toPosition(position.Line, position.Offset),
+		End:   m.toPosition(endLine, endOffset),
+	}
+}
+
+func (m *lineMapper) toPosition(line uint32, column uint32) protocol.Position {
+	return protocol.Position{Line: line, Character: m.toClient(line, column)}
+}
+
+// fromRange converts a client range to a position
+func (m *lineMapper) fromRange(clientRange protocol.Range) tokens.Position {
+	position := tokens.Position{
+		Line:   clientRange.Start.Line,
+		Offset: m.fromClient(clientRange.Start.Line, clientRange.Start.Character),
+		Byte:   uint32(m.index(clientRange.Start)),
+	}
+	endOffset := m.fromClient(clientRange.End.Line, clientRange.End.Character)
+	if clientRange.End.Line == clientRange.Start.Line {
+		position.Len = endOffset - position.Offset
+	} else {
+		position.EndLine = clientRange.End.Line
+		position.EndOffset = endOffset
+	}
+	return position
+}
+
+// byteIndex returns the byte index of column counted in encoding in line
+func byteIndex(line string, column uint32, encoding string) int {
+	var count uint32
+	for i, char := range line {
+		if count >= column {
+			return i
+		}
+		switch encoding {
+		case encodingUTF8:
+			count += uint32(utf8.RuneLen(char))
+		case encodingUTF32:
+			count += 1
+		default:
+			count += tokens.UTF16RuneLen(char)
+		}
+	}
+	return len(line)
+}
+
+// units returns the length of text counted in encoding
+func units(text string, encoding string) uint32 {
+	switch encoding {
+	case encodingUTF8:
+		return uint32(len(text))
+	case encodingUTF32:
+		return uint32(utf8.RuneCountInString(text))
+	}
+	return tokens.UTF16Len(text)
+}
@@ -0,0 +1,240 @@
+package workspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/h0rzn/dbml-lsp/analysis"
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+const (
+	CodeUnresolvedImport = "unresolved-import"
+	CodeImportCycle      = "import-cycle"
+	CodeDuplicateImport  = "duplicate-import"
+)
+
+// Symbol is a table visible in a scope
+// together with the file declaring it.
+type Symbol struct {
+	Table *symbols.Table
+	Path  string
+}
+
+// Scope contains the tables declared in a file and the
+// tables it imports. Diagnostics holds the import errors.
+type Scope struct {
+	Path        string
+	Diagnostics []*analysis.Diagnostic
+	tables      map[string]*Symbol
+}
+
+func newScope(path string) *Scope {
+	return &Scope{
+		Path:        path,
+		Diagnostics: make([]*analysis.Diagnostic, 0),
+		tables:      make(map[string]*Symbol),
+	}
+}
+
+// ResolveTable implements analysis.TableResolver
+func (s *Scope) ResolveTable(scheme string, name string) (*symbols.Table, bool) {
+	if scheme != "" {
+		name = symbols.QualifiedName(scheme, name)
+	}
+	symbol, exists := s.tables[name]
+	if !exists {
+		return nil, false
+	}
+	return symbol.Table, true
+}
+
+func (s *Scope) Lookup(name string) (*Symbol, bool) {
+	symbol, exists := s.tables[name]
+	return symbol, exists
+}
+
+// Names returns the visible table names (plain,
+// qualified and aliases) in sorted order
+func (s *Scope) Names() []string {
+	names := make([]string, 0, len(s.tables))
+	for name := range s.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the scope of the file at path
+func (w *Workspace) Resolve(path string) *Scope {
+	file := w.File(path)
+	scope := newScope(file.Path)
+	if file.Storage == nil {
+		return scope
+	}
+
+	for _, table := range file.Storage.Tables() {
+		scope.add(table.QualifiedName(), &Symbol{Table: table, Path: file.Path})
+	}
+
+	for _, imp := range file.Storage.Imports() {
+		target := ImportPath(file.Path, imp.Path)
+		exported, cycle, err := w.exports(target, []string{file.Path})
+		if cycle != nil {
+			names := make([]string, 0, len(cycle))
+			for _, path := range cycle {
+				names = append(names, relative(file.Path, path))
+			}
+			scope.report(imp, CodeImportCycle, "import cycle: %s", strings.Join(names, " -> "))
+			continue
+		}
+		if err != nil {
+			scope.report(imp, CodeUnresolvedImport, "can not resolve import %q: %s", imp.Path, err.Error())
+			continue
+		}
+
+		selected, missing := selectSymbols(imp, exported)
+		for _, item := range missing {
+			scope.report(imp, CodeUnresolvedImport, "%s %q is not declared in %q", item.Kind, item.Name, imp.Path)
+		}
+		for name, symbol := range selected {
+			if existing, exists := scope.tables[name]; exists && existing.Table != symbol.Table {
+				scope.report(imp, CodeDuplicateImport, "table %q is already declared", name)
+				continue
+			}
+			scope.add(name, symbol)
+		}
+	}
+	return scope
+}
+
+// add makes symbol visible as name. Unaliased tables, added by
+// qualified name, are visible by plain name and declared alias as
+// well; a plain name shared by several schemes resolves to the
+// table of the default scheme.
+func (s *Scope) add(name string, symbol *Symbol) {
+	s.tables[name] = symbol
+	table := symbol.Table
+	if name != table.QualifiedName() {
+		return
+	}
+	existing, exists := s.tables[table.Name]
+	if !exists || (existing.Table.Name == table.Name && table.SchemeOrDefault() == symbols.DefaultScheme) {
+		s.tables[table.Name] = symbol
+	}
+	if table.Alias != "" {
+		s.tables[table.Alias] = symbol
+	}
+}
+
+// exports returns the tables a file provides to importing files:
+// its own tables and the ones it reuses. stack holds the importing
+// files, a returned cycle lists the files forming an import cycle.
+func (w *Workspace) exports(path string, stack []string) (exported map[string]*Symbol, cycle []string, err error) {
+	for i, importer := range stack {
+		if importer == path {
+			cycle = append([]string{}, stack[i:]...)
+			return nil, append(cycle, path), nil
+		}
+	}
+
+	file := w.File(path)
+	if file.Err != nil {
+		return nil, nil, file.Err
+	}
+
+	exported = make(map[string]*Symbol)
+	for _, table := range file.Storage.Tables() {
+		exported[table.QualifiedName()] = &Symbol{Table: table, Path: file.Path}
+	}
+
+	next := append(append([]string{}, stack...), file.Path)
+	for _, imp := range file.Storage.Imports() {
+		imported, cycle, err := w.exports(ImportPath(file.Path, imp.Path), next)
+		if cycle != nil {
+			return nil, cycle, nil
+		}
+		if err != nil || !imp.Reuse {
+			// errors are reported when the file itself is resolved
+			continue
+		}
+		selected, _ := selectSymbols(imp, imported)
+		for name, symbol := range selected {
+			exported[name] = symbol
+		}
+	}
+	return exported, nil, nil
+}
+
+// selectSymbols returns the symbols of available selected by imp,
+// keyed by their alias or qualified name, and the items that were
+// not found. available is keyed the same way.
+func selectSymbols(imp *symbols.Import, available map[string]*Symbol) (map[string]*Symbol, []*symbols.ImportItem) {
+	selected := make(map[string]*Symbol)
+	missing := make([]*symbols.ImportItem, 0)
+	if imp.All {
+		for name, symbol := range available {
+			selected[name] = symbol
+		}
+		return selected, missing
+	}
+
+	for _, item := range imp.Items {
+		if item.Kind != "table" {
+			// only tables are modeled for now
+			continue
+		}
+		name, symbol, exists := lookupSymbol(available, item)
+		if !exists {
+			missing = append(missing, item)
+			continue
+		}
+		if item.Alias != "" {
+			name = item.Alias
+		}
+		selected[name] = symbol
+	}
+	return selected, missing
+}
+
+// lookupSymbol finds the symbol of item and the key it is available
+// as. Plain names match an alias, then the table of the default
+// scheme, then the only table of that name in any scheme.
+func lookupSymbol(available map[string]*Symbol, item *symbols.ImportItem) (string, *Symbol, bool) {
+	if item.Scheme != "" {
+		name := symbols.QualifiedName(item.Scheme, item.Name)
+		symbol, exists := available[name]
+		return name, symbol, exists
+	}
+	for _, name := range []string{item.Name, symbols.QualifiedName("", item.Name)} {
+		if symbol, exists := available[name]; exists {
+			return name, symbol, true
+		}
+	}
+
+	var found *Symbol
+	for name, symbol := range available {
+		if name != symbol.Table.QualifiedName() || symbol.Table.Name != item.Name {
+			continue
+		}
+		if found != nil {
+			// ambiguous
+			return "", nil, false
+		}
+		found = symbol
+	}
+	if found == nil {
+		return "", nil, false
+	}
+	return found.Table.QualifiedName(), found, true
+}
+
+func (s *Scope) report(imp *symbols.Import, code string, format string, args ...any) {
+	s.Diagnostics = append(s.Diagnostics, &analysis.Diagnostic{
+		Position: imp.Position,
+		Severity: analysis.SeverityError,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,117 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h0rzn/dbml-lsp/parser/symbols"
+)
+
+// ParseFunc parses the text of a dbml file into a symbol storage
+type ParseFunc func(text string) (*symbols.Storage, error)
+
+type File struct {
+	Path    string
+	Text    string
+	Storage *symbols.Storage
+	// Err is set if the file could not be read or parsed
+	Err error
+
+	// overlay files are open in the editor and not read from disk
+	overlay bool
+	modTime time.Time
+}
+
+// Workspace holds the files of a multi-file project.
+// Open documents are registered as overlays, all
+// other files are read from disk on demand.
+type Workspace struct {
+	mutex sync.Mutex
+	parse ParseFunc
+	files map[string]*File
+}
+
+func New(parse ParseFunc) *Workspace {
+	return &Workspace{
+		parse: parse,
+		files: make(map[string]*File),
+	}
+}
+
+// SetOverlay registers the text and parse result of an open document
+func (w *Workspace) SetOverlay(path string, text string, storage *symbols.Storage, err error) {
+	w.mutex.Lock()
+	w.files[filepath.Clean(path)] = &File{
+		Path:    filepath.Clean(path),
+		Text:    text,
+		Storage: storage,
+		Err:     err,
+		overlay: true,
+	}
+	w.mutex.Unlock()
+}
+
+// CloseOverlay falls back to the file on disk for path
+func (w *Workspace) CloseOverlay(path string) {
+	w.mutex.Lock()
+	delete(w.files, filepath.Clean(path))
+	w.mutex.Unlock()
+}
+
+// File returns the file at path, reading it from disk
+// if it is not open or changed since the last read.
+func (w *Workspace) File(path string) *File {
+	path = filepath.Clean(path)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	cached, exists := w.files[path]
+	if exists && cached.overlay {
+		return cached
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return &File{Path: path, Err: err}
+	}
+	if exists && cached.modTime.Equal(info.ModTime()) {
+		return cached
+	}
+
+	file := &File{
+		Path:    path,
+		modTime: info.ModTime(),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		file.Err = err
+	} else {
+		file.Text = string(data)
+		file.Storage, file.Err = w.parse(file.Text)
+	}
+	w.files[path] = file
+	return file
+}
+
+// ImportPath resolves the path of an import relative to the importing file
+func ImportPath(from string, importPath string) string {
+	if filepath.Ext(importPath) == "" {
+		importPath += ".dbml"
+	}
+	if filepath.IsAbs(importPath) {
+		return filepath.Clean(importPath)
+	}
+	return filepath.Join(filepath.Dir(from), importPath)
+}
+
+// relative shortens path for messages
+func relative(from string, path string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}